@@ -0,0 +1,250 @@
+// Package apnscert loads the Apple Push Notification service certificates
+// of OneSignal apps from PKCS#12 (.p12) files, and checks the expiry of the
+// certificates uploaded to OneSignal.
+//
+// It is kept apart from the onesignal package so that only the programs
+// handling certificates depend on a PKCS#12 decoder.
+//
+// Loading a certificate into an AppRequest, checked locally with its
+// password first:
+//
+//	cert, err := apnscert.SetAPNSCertificateFromFile(appRequest, "push.p12", "password")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	appRequest.APNSEnv = cert.Env
+//
+// Warning about the uploaded certificates expiring within 30 days:
+//
+//	apps, _, err := client.Apps.List()
+//	for _, w := range apnscert.Check(apps, 30*24*time.Hour) {
+//		log.Print(w)
+//	}
+package apnscert
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/tbalthazar/onesignal-go"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// ErrIncorrectPassword is returned when a PKCS#12 certificate cannot be
+// decrypted with the given password.
+var ErrIncorrectPassword = errors.New("apnscert: incorrect certificate password")
+
+// Certificate describes an APNs certificate.
+type Certificate struct {
+	// Topic is the bundle ID of the iOS app, or the website push ID for
+	// Safari, such as "com.example.app".
+	Topic string
+
+	// CommonName is the common name of the subject, such as
+	// "Apple Push Services: com.example.app".
+	CommonName string
+
+	// Env is "sandbox" for development certificates, "production"
+	// otherwise, as expected by onesignal.AppRequest.APNSEnv.
+	Env string
+
+	NotBefore time.Time
+	NotAfter  time.Time
+}
+
+// Expired tells whether the certificate has expired at t.
+func (c *Certificate) Expired(t time.Time) bool {
+	return !t.Before(c.NotAfter)
+}
+
+// oidUserID is the OID of the UID attribute of the subject, holding the
+// topic of APNs certificates.
+var oidUserID = asn1.ObjectIdentifier{0, 9, 2342, 19200300, 100, 1, 1}
+
+func newCertificate(cert *x509.Certificate) *Certificate {
+	c := &Certificate{
+		CommonName: cert.Subject.CommonName,
+		Env:        "production",
+		NotBefore:  cert.NotBefore,
+		NotAfter:   cert.NotAfter,
+	}
+	for _, name := range cert.Subject.Names {
+		if name.Type.Equal(oidUserID) {
+			c.Topic, _ = name.Value.(string)
+		}
+	}
+	if strings.Contains(c.CommonName, "Development") {
+		c.Env = "sandbox"
+	}
+	return c
+}
+
+// Parse decrypts the PKCS#12 data p12 with password, and checks that it
+// holds a certificate, still valid, along with its private key. The
+// certificates of the chain exported along with it, such as the Apple
+// intermediate certificate, are ignored. The error matches
+// ErrIncorrectPassword if the password is wrong.
+func Parse(p12 []byte, password string) (*Certificate, error) {
+	key, cert, _, err := pkcs12.DecodeChain(p12, password)
+	if errors.Is(err, pkcs12.ErrIncorrectPassword) {
+		return nil, ErrIncorrectPassword
+	}
+	if err != nil {
+		return nil, fmt.Errorf("apnscert: invalid certificate: %v", err)
+	}
+
+	if !keyMatches(key, cert) {
+		return nil, errors.New("apnscert: invalid certificate: the private key does not match the certificate")
+	}
+	c := newCertificate(cert)
+	if c.Expired(time.Now()) {
+		return c, fmt.Errorf("apnscert: certificate %q expired on %s", c.CommonName, c.NotAfter.Format(time.RFC3339))
+	}
+	return c, nil
+}
+
+// keyMatches tells whether key is the private key of cert.
+func keyMatches(key interface{}, cert *x509.Certificate) bool {
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return false
+	}
+	pub, ok := signer.Public().(interface{ Equal(crypto.PublicKey) bool })
+	return ok && pub.Equal(cert.PublicKey)
+}
+
+// SetAPNSCertificateFromFile reads the PKCS#12 (.p12) certificate at path,
+// checks it with Parse, and sets the APNSP12 and APNSP12Password fields of
+// req. req is left unchanged if the certificate is invalid.
+func SetAPNSCertificateFromFile(req *onesignal.AppRequest, path, password string) (*Certificate, error) {
+	p12, cert, err := readFile(path, password)
+	if err != nil {
+		return cert, err
+	}
+	req.APNSP12, req.APNSP12Password = p12, password
+	return cert, nil
+}
+
+// SetSafariCertificateFromFile is like SetAPNSCertificateFromFile for the
+// Safari web push certificate, setting SafariAPNSP12 and
+// SafariAPNSP12Password.
+func SetSafariCertificateFromFile(req *onesignal.AppRequest, path, password string) (*Certificate, error) {
+	p12, cert, err := readFile(path, password)
+	if err != nil {
+		return cert, err
+	}
+	req.SafariAPNSP12, req.SafariAPNSP12Password = p12, password
+	return cert, nil
+}
+
+// readFile reads and checks the certificate at path, and returns it
+// base64-encoded.
+func readFile(path, password string) (string, *Certificate, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", nil, err
+	}
+	cert, err := Parse(b, password)
+	if err != nil {
+		return "", cert, fmt.Errorf("%w (%s)", err, path)
+	}
+	return base64.StdEncoding.EncodeToString(b), cert, nil
+}
+
+// Warning reports a certificate uploaded to an app that expires soon, has
+// expired, or cannot be parsed.
+type Warning struct {
+	AppID   string
+	AppName string
+
+	// Field is the JSON name of the field of onesignal.App holding the
+	// certificate: "apns_certificates" or "safari_apns_cetificate".
+	Field string
+
+	// Certificate is nil if it cannot be parsed.
+	Certificate *Certificate
+
+	// Err tells why the certificate cannot be parsed.
+	Err error
+}
+
+func (w *Warning) String() string {
+	app := fmt.Sprintf("app %q (%s) %s", w.AppName, w.AppID, w.Field)
+	switch {
+	case w.Err != nil:
+		return fmt.Sprintf("%s: %v", app, w.Err)
+	case w.Certificate.Expired(time.Now()):
+		return fmt.Sprintf("%s: %s expired on %s", app, w.Certificate.CommonName, w.Certificate.NotAfter.Format(time.RFC3339))
+	default:
+		return fmt.Sprintf("%s: %s expires on %s", app, w.Certificate.CommonName, w.Certificate.NotAfter.Format(time.RFC3339))
+	}
+}
+
+// Check returns a warning for each APNs and Safari certificate uploaded to
+// apps that expires within d, or has already expired. The certificates are
+// read from App.APNSCertificates and App.SafariAPNSCertificate, in PEM or
+// base64-encoded DER. Apps without certificate are ignored.
+func Check(apps []onesignal.App, d time.Duration) []*Warning {
+	deadline := time.Now().Add(d)
+	var warnings []*Warning
+	for _, app := range apps {
+		for _, field := range []struct{ name, value string }{
+			{"apns_certificates", app.APNSCertificates},
+			{"safari_apns_cetificate", app.SafariAPNSCertificate},
+		} {
+			if strings.TrimSpace(field.value) == "" {
+				continue
+			}
+			w := &Warning{AppID: app.ID, AppName: app.Name, Field: field.name}
+			w.Certificate, w.Err = parseUploaded(field.value)
+			if w.Err != nil || w.Certificate.Expired(deadline) {
+				warnings = append(warnings, w)
+			}
+		}
+	}
+	return warnings
+}
+
+// parseUploaded parses a certificate returned by OneSignal, in PEM or
+// base64-encoded DER. If several certificates are given, the one expiring
+// first is returned.
+func parseUploaded(s string) (*Certificate, error) {
+	var ders [][]byte
+	rest := []byte(s)
+	for {
+		var block *pem.Block
+		if block, rest = pem.Decode(rest); block == nil {
+			break
+		}
+		if block.Type == "CERTIFICATE" {
+			ders = append(ders, block.Bytes)
+		}
+	}
+	if len(ders) == 0 {
+		der, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+		if err != nil {
+			return nil, errors.New("apnscert: unrecognized certificate format")
+		}
+		ders = append(ders, der)
+	}
+
+	var first *x509.Certificate
+	for _, der := range ders {
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, fmt.Errorf("apnscert: invalid certificate: %v", err)
+		}
+		if first == nil || cert.NotAfter.Before(first.NotAfter) {
+			first = cert
+		}
+	}
+	return newCertificate(first), nil
+}
@@ -0,0 +1,197 @@
+package apnscert
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tbalthazar/onesignal-go"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// newTestCertificate returns a self-signed APNs certificate for topic,
+// valid until notAfter, along with its private key.
+func newTestCertificate(t *testing.T, commonName, topic string, notAfter time.Time) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject: pkix.Name{
+			CommonName: commonName,
+			ExtraNames: []pkix.AttributeTypeAndValue{{Type: oidUserID, Value: topic}},
+		},
+		NotBefore: notAfter.AddDate(-1, 0, 0),
+		NotAfter:  notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, key
+}
+
+// writeTestP12 writes cert and key, along with the chain of caCerts, to a
+// .p12 file encrypted with password, and returns its path.
+func writeTestP12(t *testing.T, cert *x509.Certificate, key interface{}, password string, caCerts ...*x509.Certificate) string {
+	p12, err := pkcs12.Modern.Encode(key, cert, caCerts, password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "cert.p12")
+	if err := os.WriteFile(path, p12, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestSetAPNSCertificateFromFile(t *testing.T) {
+	notAfter := time.Now().AddDate(0, 6, 0).UTC().Truncate(time.Second)
+	cert, key := newTestCertificate(t, "Apple Development IOS Push Services: com.example.app", "com.example.app", notAfter)
+	path := writeTestP12(t, cert, key, "secret")
+
+	req := &onesignal.AppRequest{}
+	got, err := SetAPNSCertificateFromFile(req, path, "secret")
+	if err != nil {
+		t.Fatalf("SetAPNSCertificateFromFile returned an error: %v", err)
+	}
+
+	if got.Topic != "com.example.app" || got.Env != "sandbox" || !got.NotAfter.Equal(notAfter) {
+		t.Errorf("SetAPNSCertificateFromFile returned %+v", got)
+	}
+	p12, _ := os.ReadFile(path)
+	if req.APNSP12 != base64.StdEncoding.EncodeToString(p12) || req.APNSP12Password != "secret" {
+		t.Errorf("APNSP12 is %q and APNSP12Password %q", req.APNSP12, req.APNSP12Password)
+	}
+	if req.SafariAPNSP12 != "" {
+		t.Errorf("SafariAPNSP12 is %q, want it empty", req.SafariAPNSP12)
+	}
+}
+
+func TestSetSafariCertificateFromFile(t *testing.T) {
+	cert, key := newTestCertificate(t, "Website Push ID: web.com.example", "web.com.example", time.Now().AddDate(1, 0, 0))
+	path := writeTestP12(t, cert, key, "")
+
+	req := &onesignal.AppRequest{}
+	got, err := SetSafariCertificateFromFile(req, path, "")
+	if err != nil {
+		t.Fatalf("SetSafariCertificateFromFile returned an error: %v", err)
+	}
+	if got.Topic != "web.com.example" || got.Env != "production" {
+		t.Errorf("SetSafariCertificateFromFile returned %+v", got)
+	}
+	if req.SafariAPNSP12 == "" || req.APNSP12 != "" {
+		t.Errorf("SafariAPNSP12 is %q and APNSP12 %q", req.SafariAPNSP12, req.APNSP12)
+	}
+}
+
+func TestSetAPNSCertificateFromFile_invalid(t *testing.T) {
+	cert, key := newTestCertificate(t, "Apple Push Services: com.example.app", "com.example.app", time.Now().AddDate(1, 0, 0))
+	expired, expiredKey := newTestCertificate(t, "Apple Push Services: com.example.app", "com.example.app", time.Now().Add(-time.Hour))
+	_, otherKey := newTestCertificate(t, "Apple Push Services: com.example.app", "com.example.app", time.Now().AddDate(1, 0, 0))
+	notP12 := filepath.Join(t.TempDir(), "cert.pem")
+	os.WriteFile(notP12, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}), 0600)
+
+	tests := []struct {
+		path     string
+		password string
+		want     string
+	}{
+		{writeTestP12(t, cert, key, "secret"), "wrong", "incorrect certificate password"},
+		{writeTestP12(t, expired, expiredKey, "secret"), "secret", "expired"},
+		{writeTestP12(t, cert, otherKey, "secret"), "secret", "private key does not match"},
+		{notP12, "secret", "invalid certificate"},
+		{filepath.Join(t.TempDir(), "missing.p12"), "secret", "no such file"},
+	}
+	for _, tt := range tests {
+		req := &onesignal.AppRequest{}
+		_, err := SetAPNSCertificateFromFile(req, tt.path, tt.password)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("SetAPNSCertificateFromFile(%v) returned %v, want an error containing %q", tt.path, err, tt.want)
+		}
+		if req.APNSP12 != "" || req.APNSP12Password != "" {
+			t.Errorf("SetAPNSCertificateFromFile(%v) changed the request: %+v", tt.path, req)
+		}
+	}
+
+	_, err := Parse(mustReadFile(t, tests[0].path), "wrong")
+	if !errors.Is(err, ErrIncorrectPassword) {
+		t.Errorf("Parse returned %v, want ErrIncorrectPassword", err)
+	}
+}
+
+func TestParse_chain(t *testing.T) {
+	// Apple exports the intermediate certificate along with the APNs one
+	ca, _ := newTestCertificate(t, "Apple Worldwide Developer Relations Certification Authority", "", time.Now().AddDate(2, 0, 0))
+	cert, key := newTestCertificate(t, "Apple Push Services: com.example.app", "com.example.app", time.Now().AddDate(1, 0, 0))
+
+	got, err := Parse(mustReadFile(t, writeTestP12(t, cert, key, "secret", ca)), "secret")
+	if err != nil {
+		t.Fatalf("Parse returned an error: %v", err)
+	}
+	if got.Topic != "com.example.app" || got.CommonName != "Apple Push Services: com.example.app" {
+		t.Errorf("Parse returned %+v, want the APNs certificate", got)
+	}
+}
+
+func mustReadFile(t *testing.T, path string) []byte {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestCheck(t *testing.T) {
+	now := time.Now()
+	soon, _ := newTestCertificate(t, "Apple Push Services: com.example.soon", "com.example.soon", now.AddDate(0, 0, 10))
+	later, _ := newTestCertificate(t, "Apple Push Services: com.example.later", "com.example.later", now.AddDate(1, 0, 0))
+	expired, _ := newTestCertificate(t, "Website Push ID: web.com.example", "web.com.example", now.AddDate(0, 0, -1))
+	toPEM := func(certs ...*x509.Certificate) string {
+		var b []byte
+		for _, cert := range certs {
+			b = append(b, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})...)
+		}
+		return string(b)
+	}
+
+	apps := []onesignal.App{
+		{ID: "app1", Name: "Soon", APNSCertificates: toPEM(later, soon)},
+		{ID: "app2", Name: "Later", APNSCertificates: base64.StdEncoding.EncodeToString(later.Raw), SafariAPNSCertificate: toPEM(expired)},
+		{ID: "app3", Name: "None"},
+		{ID: "app4", Name: "Invalid", APNSCertificates: "Your apns certificate"},
+	}
+	warnings := Check(apps, 30*24*time.Hour)
+	if len(warnings) != 3 {
+		t.Fatalf("Check returned %d warnings, want 3: %v", len(warnings), warnings)
+	}
+
+	if w := warnings[0]; w.AppID != "app1" || w.Field != "apns_certificates" || w.Certificate.Topic != "com.example.soon" {
+		t.Errorf("first warning is %+v", w)
+	}
+	if got := warnings[0].String(); !strings.Contains(got, `app "Soon" (app1) apns_certificates: Apple Push Services: com.example.soon expires on`) {
+		t.Errorf("first warning is %q", got)
+	}
+	if w := warnings[1]; w.AppID != "app2" || w.Field != "safari_apns_cetificate" || !strings.Contains(w.String(), "expired on") {
+		t.Errorf("second warning is %v", w)
+	}
+	if w := warnings[2]; w.AppID != "app4" || w.Err == nil || w.Certificate != nil {
+		t.Errorf("third warning is %v", w)
+	}
+}
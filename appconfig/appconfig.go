@@ -0,0 +1,445 @@
+// Package appconfig manages OneSignal apps declaratively: the desired state
+// of the apps is read from a YAML or JSON file, compared with their current
+// state to make a plan, and the plan is applied with the apps API.
+//
+// The file lists the apps with the fields of onesignal.AppRequest, and the
+// ID of the apps that already exist:
+//
+//	apps:
+//	  - id: 92911750-242d-4260-9e00-9d9034f139ce
+//	    name: Production
+//	    gcm_key: YourGCMKey
+//	    safari_site_origin: https://example.com
+//	  - name: Staging
+//	    site_name: Staging
+//
+// An app without ID is matched with the existing app of the same name, or
+// created if there is none. Only the fields set in the file are managed:
+// the others are left as they are.
+//
+// Making and applying a plan:
+//
+//	cfg, err := appconfig.Load("apps.yaml")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	plan, err := appconfig.NewPlan(ctx, client, cfg)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	plan.WriteTo(os.Stdout)
+//	if _, err := plan.Apply(ctx, client); err != nil {
+//		log.Fatal(err)
+//	}
+package appconfig
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/tbalthazar/onesignal-go"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the desired state of apps.
+type Config struct {
+	Apps []App `json:"apps"`
+}
+
+// App is the desired state of an app.
+type App struct {
+	// ID of the app, empty to match the app by name.
+	ID string `json:"id,omitempty"`
+
+	onesignal.AppRequest
+}
+
+// Load reads the config from the YAML or JSON file at path.
+func Load(path string) (*Config, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := Parse(b)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return cfg, nil
+}
+
+// errNoApps is returned by Parse for a config without apps, which is more
+// likely a mistake than the intent to manage no app.
+var errNoApps = errors.New("appconfig: no apps")
+
+// Parse parses a YAML or JSON config. Unknown fields are rejected, so that a
+// misspelled field is not silently left unmanaged.
+//
+// The fields of the apps are all strings: unquoted YAML numbers and
+// booleans, such as chrome_web_gcm_sender_id: 123456, are read as written.
+func Parse(b []byte) (*Config, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, fmt.Errorf("appconfig: %v", err)
+	}
+	quoteAppScalars(&doc)
+	j, err := nodeToJSON(&doc)
+	if err != nil {
+		return nil, fmt.Errorf("appconfig: %v", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(j))
+	dec.DisallowUnknownFields()
+	cfg := &Config{}
+	if err := dec.Decode(cfg); err != nil {
+		return nil, fmt.Errorf("appconfig: %v", err)
+	}
+
+	if len(cfg.Apps) == 0 {
+		return nil, errNoApps
+	}
+	for i, app := range cfg.Apps {
+		if app.ID == "" && app.Name == "" {
+			return nil, fmt.Errorf("appconfig: app %d has neither id nor name", i+1)
+		}
+	}
+	return cfg, nil
+}
+
+// YAMLToJSON converts the YAML or JSON document b to JSON. YAML is a
+// superset of JSON: decoding either, then going through JSON, makes the
+// json tags of the type it is finally decoded into apply.
+//
+// Unquoted YAML numbers and booleans become JSON numbers and booleans,
+// which don't decode into string fields: quote them, as in
+// chrome_web_gcm_sender_id: "123456". Parse does so for the apps.
+func YAMLToJSON(b []byte) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+	return nodeToJSON(&doc)
+}
+
+func nodeToJSON(n *yaml.Node) ([]byte, error) {
+	var v interface{}
+	if err := n.Decode(&v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// quoteAppScalars tags the numbers and booleans set to the fields of the
+// apps of doc as strings, so that they decode to their text, leading zeros
+// included, rather than to JSON values that don't fit the string fields.
+func quoteAppScalars(doc *yaml.Node) {
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return
+	}
+	root := doc.Content[0]
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value != "apps" || root.Content[i+1].Kind != yaml.SequenceNode {
+			continue
+		}
+		for _, app := range root.Content[i+1].Content {
+			if app.Kind != yaml.MappingNode {
+				continue
+			}
+			for j := 1; j < len(app.Content); j += 2 {
+				switch v := app.Content[j]; v.ShortTag() {
+				case "!!int", "!!float", "!!bool":
+					v.Tag = "!!str"
+				}
+			}
+		}
+	}
+}
+
+// Action is what applying a plan does to an app.
+type Action int
+
+const (
+	// NoOp leaves the app unchanged.
+	NoOp Action = iota
+
+	// Create creates the app.
+	Create
+
+	// Update updates the fields of the app listed in the changes.
+	Update
+)
+
+// Change is a field of an app to change.
+type Change struct {
+	// Field is the JSON name of the field, such as gcm_key.
+	Field string
+
+	// Old is the current value, empty when the app is created.
+	Old string
+
+	// New is the desired value.
+	New string
+
+	// Sensitive tells whether the values are secrets, masked when the
+	// change is printed.
+	Sensitive bool
+
+	// WriteOnly tells whether the current value cannot be read from
+	// OneSignal, such as for the APNs certificates.
+	WriteOnly bool
+}
+
+// String returns the change as printed in a plan, with the secrets masked.
+func (c Change) String() string {
+	newValue := mask(c.New, c.Sensitive)
+	switch {
+	case c.WriteOnly:
+		return fmt.Sprintf("%s: %s (write-only)", c.Field, newValue)
+	case c.Old == "":
+		return fmt.Sprintf("%s: %s", c.Field, newValue)
+	default:
+		return fmt.Sprintf("%s: %s => %s", c.Field, mask(c.Old, c.Sensitive), newValue)
+	}
+}
+
+func mask(s string, sensitive bool) string {
+	if sensitive {
+		return "(sensitive)"
+	}
+	return fmt.Sprintf("%q", s)
+}
+
+// AppPlan is the plan for an app.
+type AppPlan struct {
+	Action Action
+
+	// AppID is the ID of the app, empty when it is created.
+	AppID string
+
+	// Name is the name of the app, as configured or else as it is.
+	Name string
+
+	Changes []Change
+
+	// Request is sent to create or update the app. It only holds the
+	// changed fields when updating.
+	Request *onesignal.AppRequest
+}
+
+// Plan holds the changes needed to bring the apps to their desired state.
+type Plan struct {
+	Apps []AppPlan
+}
+
+// PlanOption configures NewPlan.
+type PlanOption func(*planOptions)
+
+type planOptions struct {
+	writeOnly bool
+}
+
+// WithWriteOnly makes the plan update the write-only fields set in the
+// config, such as the APNs certificates and their passwords. Their current
+// value cannot be read from OneSignal, so they are otherwise only sent when
+// an app is created.
+func WithWriteOnly(writeOnly bool) PlanOption {
+	return func(o *planOptions) {
+		o.writeOnly = writeOnly
+	}
+}
+
+// NewPlan compares the apps of cfg with their current state and returns
+// the plan to apply.
+func NewPlan(ctx context.Context, client *onesignal.Client, cfg *Config, opts ...PlanOption) (*Plan, error) {
+	o := &planOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	// the apps are only listed if some of them are matched by name
+	var apps []onesignal.App
+	for _, app := range cfg.Apps {
+		if app.ID == "" {
+			var err error
+			if apps, _, err = client.Apps.ListWithContext(ctx); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+
+	plan := &Plan{}
+	for _, desired := range cfg.Apps {
+		var current *onesignal.App
+		if desired.ID != "" {
+			app, _, err := client.Apps.GetWithContext(ctx, desired.ID)
+			if err != nil {
+				return nil, fmt.Errorf("appconfig: app %s: %w", desired.ID, err)
+			}
+			current = app
+		} else {
+			for i := range apps {
+				if apps[i].Name == desired.Name {
+					if current != nil {
+						return nil, fmt.Errorf("appconfig: several apps are named %q, set the id of the app", desired.Name)
+					}
+					current = &apps[i]
+				}
+			}
+		}
+		plan.Apps = append(plan.Apps, planApp(&desired.AppRequest, current, o))
+	}
+	return plan, nil
+}
+
+// planApp returns the plan to bring current to desired, creating it if
+// current is nil.
+func planApp(desired *onesignal.AppRequest, current *onesignal.App, o *planOptions) AppPlan {
+	if current == nil {
+		p := AppPlan{Action: Create, Name: desired.Name, Request: desired}
+		for _, f := range appFields {
+			if v := reflect.ValueOf(desired).Elem().Field(f.request).String(); v != "" {
+				p.Changes = append(p.Changes, Change{Field: f.name, New: v, Sensitive: f.sensitive, WriteOnly: f.app < 0})
+			}
+		}
+		return p
+	}
+
+	p := AppPlan{Action: NoOp, AppID: current.ID, Name: current.Name, Request: &onesignal.AppRequest{}}
+	if desired.Name != "" {
+		p.Name = desired.Name
+	}
+	req := reflect.ValueOf(p.Request).Elem()
+	for _, f := range appFields {
+		v := reflect.ValueOf(desired).Elem().Field(f.request).String()
+		if v == "" {
+			continue
+		}
+		c := Change{Field: f.name, New: v, Sensitive: f.sensitive}
+		if f.app < 0 {
+			if !o.writeOnly {
+				continue
+			}
+			c.WriteOnly = true
+		} else {
+			c.Old = reflect.ValueOf(current).Elem().Field(f.app).String()
+			if c.Old == v {
+				continue
+			}
+		}
+		req.Field(f.request).SetString(v)
+		p.Changes = append(p.Changes, c)
+	}
+	if len(p.Changes) > 0 {
+		p.Action = Update
+	}
+	return p
+}
+
+// appField is a string field of AppRequest, along with the index of the
+// matching field of App, or -1 if it is write-only.
+type appField struct {
+	name      string
+	request   int
+	app       int
+	sensitive bool
+}
+
+// appFields are the fields of AppRequest, matched with the fields of App by
+// JSON name. Plans compare and copy them as strings: a field of another kind
+// panics at init, to be handled explicitly rather than planned wrongly.
+var appFields = func() []appField {
+	appIndex := make(map[string]int)
+	appType := reflect.TypeOf(onesignal.App{})
+	for i := 0; i < appType.NumField(); i++ {
+		appIndex[jsonName(appType.Field(i))] = i
+	}
+
+	var fields []appField
+	requestType := reflect.TypeOf(onesignal.AppRequest{})
+	for i := 0; i < requestType.NumField(); i++ {
+		mustBeString(requestType, requestType.Field(i))
+		name := jsonName(requestType.Field(i))
+		f := appField{name: name, request: i, app: -1, sensitive: onesignal.IsSensitiveField(name)}
+		if j, ok := appIndex[name]; ok {
+			mustBeString(appType, appType.Field(j))
+			f.app = j
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}()
+
+func mustBeString(t reflect.Type, f reflect.StructField) {
+	if f.Type.Kind() != reflect.String {
+		panic(fmt.Sprintf("appconfig: %s.%s is a %s, only string fields are supported", t.Name(), f.Name, f.Type))
+	}
+}
+
+func jsonName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	return name
+}
+
+// HasChanges tells whether applying p changes any app.
+func (p *Plan) HasChanges() bool {
+	for _, app := range p.Apps {
+		if app.Action != NoOp {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteTo writes p in a human readable form to w, with the secrets masked.
+func (p *Plan) WriteTo(w io.Writer) (int64, error) {
+	var b bytes.Buffer
+	var create, update, noop int
+	for _, app := range p.Apps {
+		switch app.Action {
+		case Create:
+			create++
+			fmt.Fprintf(&b, "+ create app %q\n", app.Name)
+		case Update:
+			update++
+			fmt.Fprintf(&b, "~ update app %q (%s)\n", app.Name, app.AppID)
+		default:
+			noop++
+			fmt.Fprintf(&b, "  app %q (%s) is up to date\n", app.Name, app.AppID)
+		}
+		for _, c := range app.Changes {
+			fmt.Fprintf(&b, "    %s\n", c)
+		}
+	}
+	fmt.Fprintf(&b, "\nPlan: %d to create, %d to update, %d unchanged.\n", create, update, noop)
+	return b.WriteTo(w)
+}
+
+// Apply applies p, and returns the apps created or updated, in the order of
+// the plan. If an app fails, the apps applied so far are returned along
+// with the error.
+func (p *Plan) Apply(ctx context.Context, client *onesignal.Client) ([]onesignal.App, error) {
+	var apps []onesignal.App
+	for _, app := range p.Apps {
+		var res *onesignal.App
+		var err error
+		switch app.Action {
+		case Create:
+			res, _, err = client.Apps.CreateWithContext(ctx, app.Request)
+		case Update:
+			res, _, err = client.Apps.UpdateWithContext(ctx, app.AppID, app.Request)
+		default:
+			continue
+		}
+		if err != nil {
+			return apps, fmt.Errorf("appconfig: app %q: %w", app.Name, err)
+		}
+		apps = append(apps, *res)
+	}
+	return apps, nil
+}
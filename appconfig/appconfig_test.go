@@ -0,0 +1,252 @@
+package appconfig
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tbalthazar/onesignal-go"
+	"github.com/tbalthazar/onesignal-go/onesignaltest"
+)
+
+func TestLoad(t *testing.T) {
+	cfg, err := Load("test-fixtures/apps.yaml")
+	if err != nil {
+		t.Fatalf("Load returned an error: %v", err)
+	}
+
+	want := &Config{Apps: []App{
+		{ID: "production-app-id", AppRequest: onesignal.AppRequest{
+			Name:             "Production",
+			GCMKey:           "new-gcm-key",
+			SafariSiteOrigin: "https://example.com",
+			APNSP12:          "base64-p12",
+		}},
+		{AppRequest: onesignal.AppRequest{Name: "Staging", SiteName: "Staging site"}},
+		{AppRequest: onesignal.AppRequest{Name: "Preview", ChromeWebOrigin: "https://preview.example.com"}},
+	}}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("Load returned %+v, want %+v", cfg, want)
+	}
+}
+
+func TestParse_JSON(t *testing.T) {
+	cfg, err := Parse([]byte(`{"apps": [{"name": "My app", "gcm_key": "key"}]}`))
+	if err != nil {
+		t.Fatalf("Parse returned an error: %v", err)
+	}
+	if got, want := cfg.Apps[0].AppRequest, (onesignal.AppRequest{Name: "My app", GCMKey: "key"}); got != want {
+		t.Errorf("Parse returned %+v, want %+v", got, want)
+	}
+}
+
+func TestParse_unquotedScalars(t *testing.T) {
+	cfg, err := Parse([]byte("apps:\n" +
+		"  - name: 2024\n" +
+		"    chrome_web_gcm_sender_id: 0123456\n" +
+		"    site_name: true\n"))
+	if err != nil {
+		t.Fatalf("Parse returned an error: %v", err)
+	}
+	want := onesignal.AppRequest{Name: "2024", ChromeWebGCMSenderID: "0123456", SiteName: "true"}
+	if got := cfg.Apps[0].AppRequest; got != want {
+		t.Errorf("Parse returned %+v, want %+v", got, want)
+	}
+}
+
+func TestAppFields(t *testing.T) {
+	// every field of AppRequest is planned
+	if got, want := len(appFields), reflect.TypeOf(onesignal.AppRequest{}).NumField(); got != want {
+		t.Errorf("appFields has %d fields, want %d", got, want)
+	}
+	for _, f := range appFields {
+		if f.name == "gcm_key" && !f.sensitive {
+			t.Errorf("gcm_key should be sensitive")
+		}
+	}
+}
+
+func TestParse_invalid(t *testing.T) {
+	for _, content := range []string{
+		"apps: [\n",
+		"apps:\n  - name: My app\n    gcm_kye: key\n",
+		"apps:\n  - site_name: No name\n",
+		"apps: []\n",
+	} {
+		if _, err := Parse([]byte(content)); err == nil {
+			t.Errorf("Parse(%q) should have returned an error", content)
+		}
+	}
+}
+
+// setup starts a fake OneSignal server with the production and staging
+// apps, and returns a client of it along with the config of the fixture.
+func setup(t *testing.T) (*onesignaltest.Server, *onesignal.Client, *Config) {
+	srv := onesignaltest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.AddApp(onesignal.App{ID: "production-app-id", Name: "Production", GCMKey: "old-gcm-key", SafariSiteOrigin: "https://example.com"})
+	srv.AddApp(onesignal.App{ID: "staging-app-id", Name: "Staging", SiteName: "Staging site"})
+
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatalf("Client returned an error: %v", err)
+	}
+	cfg, err := Load("test-fixtures/apps.yaml")
+	if err != nil {
+		t.Fatalf("Load returned an error: %v", err)
+	}
+	return srv, client, cfg
+}
+
+func TestNewPlan(t *testing.T) {
+	_, client, cfg := setup(t)
+
+	plan, err := NewPlan(context.Background(), client, cfg)
+	if err != nil {
+		t.Fatalf("NewPlan returned an error: %v", err)
+	}
+
+	want := &Plan{Apps: []AppPlan{
+		{
+			Action:  Update,
+			AppID:   "production-app-id",
+			Name:    "Production",
+			Changes: []Change{{Field: "gcm_key", Old: "old-gcm-key", New: "new-gcm-key", Sensitive: true}},
+			Request: &onesignal.AppRequest{GCMKey: "new-gcm-key"},
+		},
+		{
+			Action:  NoOp,
+			AppID:   "staging-app-id",
+			Name:    "Staging",
+			Request: &onesignal.AppRequest{},
+		},
+		{
+			Action:  Create,
+			Name:    "Preview",
+			Changes: []Change{{Field: "name", New: "Preview"}, {Field: "chrome_web_origin", New: "https://preview.example.com"}},
+			Request: &cfg.Apps[2].AppRequest,
+		},
+	}}
+	if !reflect.DeepEqual(plan, want) {
+		t.Errorf("NewPlan returned %+v, want %+v", plan, want)
+	}
+	if !plan.HasChanges() {
+		t.Errorf("HasChanges returned false, want true")
+	}
+}
+
+func TestNewPlan_writeOnly(t *testing.T) {
+	_, client, cfg := setup(t)
+
+	plan, err := NewPlan(context.Background(), client, cfg, WithWriteOnly(true))
+	if err != nil {
+		t.Fatalf("NewPlan returned an error: %v", err)
+	}
+	want := []Change{
+		{Field: "gcm_key", Old: "old-gcm-key", New: "new-gcm-key", Sensitive: true},
+		{Field: "apns_p12", New: "base64-p12", Sensitive: true, WriteOnly: true},
+	}
+	if got := plan.Apps[0].Changes; !reflect.DeepEqual(got, want) {
+		t.Errorf("changes are %+v, want %+v", got, want)
+	}
+	if got := plan.Apps[0].Request.APNSP12; got != "base64-p12" {
+		t.Errorf("APNSP12 is %q, want base64-p12", got)
+	}
+}
+
+func TestNewPlan_errors(t *testing.T) {
+	srv, client, _ := setup(t)
+
+	cfg := &Config{Apps: []App{{ID: "unknown-app-id"}}}
+	if _, err := NewPlan(context.Background(), client, cfg); !errors.Is(err, onesignal.ErrNotFound) {
+		t.Errorf("NewPlan returned %v, want ErrNotFound", err)
+	}
+
+	srv.AddApp(onesignal.App{Name: "Staging"})
+	cfg = &Config{Apps: []App{{AppRequest: onesignal.AppRequest{Name: "Staging"}}}}
+	if _, err := NewPlan(context.Background(), client, cfg); err == nil || !strings.Contains(err.Error(), "several apps") {
+		t.Errorf("NewPlan returned %v, want an error about the apps with the same name", err)
+	}
+}
+
+func TestPlan_WriteTo(t *testing.T) {
+	_, client, cfg := setup(t)
+	plan, err := NewPlan(context.Background(), client, cfg, WithWriteOnly(true))
+	if err != nil {
+		t.Fatalf("NewPlan returned an error: %v", err)
+	}
+
+	var b strings.Builder
+	plan.WriteTo(&b)
+	want := `~ update app "Production" (production-app-id)
+    gcm_key: (sensitive) => (sensitive)
+    apns_p12: (sensitive) (write-only)
+  app "Staging" (staging-app-id) is up to date
++ create app "Preview"
+    name: "Preview"
+    chrome_web_origin: "https://preview.example.com"
+
+Plan: 1 to create, 1 to update, 1 unchanged.
+`
+	if got := b.String(); got != want {
+		t.Errorf("WriteTo wrote:\n%s\nwant:\n%s", got, want)
+	}
+	if strings.Contains(b.String(), "gcm-key") || strings.Contains(b.String(), "base64") {
+		t.Errorf("WriteTo wrote secrets")
+	}
+}
+
+func TestPlan_Apply(t *testing.T) {
+	srv, client, cfg := setup(t)
+	plan, err := NewPlan(context.Background(), client, cfg)
+	if err != nil {
+		t.Fatalf("NewPlan returned an error: %v", err)
+	}
+
+	apps, err := plan.Apply(context.Background(), client)
+	if err != nil {
+		t.Fatalf("Apply returned an error: %v", err)
+	}
+	if len(apps) != 2 || apps[0].ID != "production-app-id" || apps[1].Name != "Preview" {
+		t.Errorf("Apply returned %+v", apps)
+	}
+
+	got := srv.Apps()
+	if len(got) != 3 {
+		t.Fatalf("the server has %d apps, want 3", len(got))
+	}
+	if got[0].GCMKey != "new-gcm-key" || got[0].SafariSiteOrigin != "https://example.com" {
+		t.Errorf("the production app is %+v", got[0])
+	}
+	if got[2].Name != "Preview" || got[2].ChromeWebOrigin != "https://preview.example.com" {
+		t.Errorf("the preview app is %+v", got[2])
+	}
+
+	// once applied, there is nothing left to do
+	plan, err = NewPlan(context.Background(), client, cfg)
+	if err != nil {
+		t.Fatalf("NewPlan returned an error: %v", err)
+	}
+	if plan.HasChanges() {
+		t.Errorf("the plan has changes after being applied: %+v", plan)
+	}
+}
+
+func TestPlan_Apply_error(t *testing.T) {
+	srv, client, cfg := setup(t)
+	plan, err := NewPlan(context.Background(), client, cfg)
+	if err != nil {
+		t.Fatalf("NewPlan returned an error: %v", err)
+	}
+
+	srv.InjectFault(onesignaltest.Fault{Method: "POST", Path: "/apps", StatusCode: 400})
+	apps, err := plan.Apply(context.Background(), client)
+	if err == nil || !strings.Contains(err.Error(), `"Preview"`) {
+		t.Errorf("Apply returned %v, want an error about the preview app", err)
+	}
+	if len(apps) != 1 || apps[0].ID != "production-app-id" {
+		t.Errorf("Apply returned %+v, want the production app", apps)
+	}
+}
@@ -1,6 +1,7 @@
 package onesignal
 
 import (
+	"context"
 	"net/http"
 	"net/url"
 	"time"
@@ -68,6 +69,14 @@ type AppRequest struct {
 //
 // OneSignal API docs: https://documentation.onesignal.com/docs/apps-view-apps
 func (s *AppsService) List() ([]App, *http.Response, error) {
+	return s.ListWithContext(context.Background())
+}
+
+// ListWithContext is like List but takes a context.Context that
+// controls the lifetime of the request.
+func (s *AppsService) ListWithContext(ctx context.Context) ([]App, *http.Response, error) {
+	ctx = withOperation(ctx, Operation{Name: "apps.list"})
+
 	// build the URL
 	u, err := url.Parse("/apps")
 	if err != nil {
@@ -75,7 +84,7 @@ func (s *AppsService) List() ([]App, *http.Response, error) {
 	}
 
 	// create the request
-	req, err := s.client.NewRequest("GET", u.String(), nil, USER)
+	req, err := s.client.NewRequestWithContext(ctx, "GET", u.String(), nil, USER)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -93,6 +102,14 @@ func (s *AppsService) List() ([]App, *http.Response, error) {
 //
 // OneSignal API docs: https://documentation.onesignal.com/docs/appsid
 func (s *AppsService) Get(appID string) (*App, *http.Response, error) {
+	return s.GetWithContext(context.Background(), appID)
+}
+
+// GetWithContext is like Get but takes a context.Context that
+// controls the lifetime of the request.
+func (s *AppsService) GetWithContext(ctx context.Context, appID string) (*App, *http.Response, error) {
+	ctx = withOperation(ctx, Operation{Name: "apps.get", AppID: appID})
+
 	// build the URL
 	u, err := url.Parse("/apps/" + appID)
 	if err != nil {
@@ -100,7 +117,7 @@ func (s *AppsService) Get(appID string) (*App, *http.Response, error) {
 	}
 
 	// create the request
-	req, err := s.client.NewRequest("GET", u.String(), nil, USER)
+	req, err := s.client.NewRequestWithContext(ctx, "GET", u.String(), nil, USER)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -118,6 +135,14 @@ func (s *AppsService) Get(appID string) (*App, *http.Response, error) {
 //
 // OneSignal API docs: https://documentation.onesignal.com/docs/apps-create-an-app
 func (s *AppsService) Create(opt *AppRequest) (*App, *http.Response, error) {
+	return s.CreateWithContext(context.Background(), opt)
+}
+
+// CreateWithContext is like Create but takes a context.Context that
+// controls the lifetime of the request.
+func (s *AppsService) CreateWithContext(ctx context.Context, opt *AppRequest) (*App, *http.Response, error) {
+	ctx = withOperation(ctx, Operation{Name: "apps.create"})
+
 	// build the URL
 	u, err := url.Parse("/apps")
 	if err != nil {
@@ -125,7 +150,7 @@ func (s *AppsService) Create(opt *AppRequest) (*App, *http.Response, error) {
 	}
 
 	// create the request
-	req, err := s.client.NewRequest("POST", u.String(), opt, USER)
+	req, err := s.client.NewRequestWithContext(ctx, "POST", u.String(), opt, USER)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -143,6 +168,14 @@ func (s *AppsService) Create(opt *AppRequest) (*App, *http.Response, error) {
 //
 // OneSignal API docs: https://documentation.onesignal.com/docs/appsid-update-an-app
 func (s *AppsService) Update(appID string, opt *AppRequest) (*App, *http.Response, error) {
+	return s.UpdateWithContext(context.Background(), appID, opt)
+}
+
+// UpdateWithContext is like Update but takes a context.Context that
+// controls the lifetime of the request.
+func (s *AppsService) UpdateWithContext(ctx context.Context, appID string, opt *AppRequest) (*App, *http.Response, error) {
+	ctx = withOperation(ctx, Operation{Name: "apps.update", AppID: appID})
+
 	// build the URL
 	u, err := url.Parse("/apps/" + appID)
 	if err != nil {
@@ -150,7 +183,7 @@ func (s *AppsService) Update(appID string, opt *AppRequest) (*App, *http.Respons
 	}
 
 	// create the request
-	req, err := s.client.NewRequest("PUT", u.String(), opt, USER)
+	req, err := s.client.NewRequestWithContext(ctx, "PUT", u.String(), opt, USER)
 	if err != nil {
 		return nil, nil, err
 	}
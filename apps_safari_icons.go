@@ -0,0 +1,166 @@
+package onesignal
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/jpeg" // decode JPEG source images
+	"image/png"
+	"io"
+	"math"
+	"os"
+)
+
+// SafariIconSizes are the sizes, in pixels, of the icons of Safari web
+// push: 16x16, 32x32, 64x64, 128x128 and 256x256.
+var SafariIconSizes = []int{16, 32, 64, 128, 256}
+
+// SafariIcons holds the PNG-encoded Safari web push icons by size.
+type SafariIcons map[int][]byte
+
+// NewSafariIcons decodes a square PNG or JPEG image of at least 256x256
+// pixels from r, and resizes it to every size of SafariIconSizes.
+func NewSafariIcons(r io.Reader) (SafariIcons, error) {
+	src, _, err := image.Decode(r)
+	if err != nil {
+		return nil, fmt.Errorf("onesignal: invalid icon image: %v", err)
+	}
+	b := src.Bounds()
+	if b.Dx() != b.Dy() {
+		return nil, fmt.Errorf("onesignal: icon image is %dx%d, it must be square", b.Dx(), b.Dy())
+	}
+	if largest := SafariIconSizes[len(SafariIconSizes)-1]; b.Dx() < largest {
+		return nil, fmt.Errorf("onesignal: icon image is %dx%d, it must be at least %dx%d", b.Dx(), b.Dy(), largest, largest)
+	}
+
+	// work on premultiplied RGBA, so that transparent pixels do not bleed
+	// their color into the resized icons
+	rgba := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), src, b.Min, draw.Src)
+
+	icons := make(SafariIcons, len(SafariIconSizes))
+	for _, size := range SafariIconSizes {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, resizeSquare(rgba, size)); err != nil {
+			return nil, err
+		}
+		icons[size] = buf.Bytes()
+	}
+	return icons, nil
+}
+
+// SetSafariIcons uploads icons with upload, which stores the PNG of the
+// icon of a size and returns the URL it is served at, and sets the Safari
+// icon fields of r to these URLs, as OneSignal expects the URLs of hosted
+// PNG images. r is left unchanged if an upload fails.
+//
+// The fields of the sizes missing from icons are left unchanged.
+func (r *AppRequest) SetSafariIcons(icons SafariIcons, upload func(size int, png []byte) (string, error)) error {
+	urls := make(map[int]string, len(icons))
+	for _, size := range SafariIconSizes {
+		if icon, ok := icons[size]; ok {
+			url, err := upload(size, icon)
+			if err != nil {
+				return fmt.Errorf("onesignal: uploading the %dx%d icon: %w", size, size, err)
+			}
+			urls[size] = url
+		}
+	}
+
+	fields := map[int]*string{
+		16:  &r.SafariIcon1616,
+		32:  &r.SafariIcon3232,
+		64:  &r.SafariIcon6464,
+		128: &r.SafariIcon128128,
+		256: &r.SafariIcon256256,
+	}
+	for size, url := range urls {
+		*fields[size] = url
+	}
+	return nil
+}
+
+// SetSafariIconsFromFile generates the Safari icons from the PNG or JPEG
+// image at path with NewSafariIcons, and uploads and sets them with
+// SetSafariIcons.
+func (r *AppRequest) SetSafariIconsFromFile(path string, upload func(size int, png []byte) (string, error)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	icons, err := NewSafariIcons(f)
+	if err != nil {
+		return fmt.Errorf("%w (%s)", err, path)
+	}
+	return r.SetSafariIcons(icons, upload)
+}
+
+// resizeSquare shrinks the square image src, whose bounds start at 0, to
+// size x size pixels with a box filter: each pixel is the average of the
+// pixels of src it covers, weighted by how much it covers them.
+func resizeSquare(src *image.RGBA, size int) *image.RGBA {
+	n := src.Bounds().Dx()
+	spans := boxSpans(n, size)
+
+	// resize the rows, then the columns
+	rows := make([]float64, n*size*4)
+	for y := 0; y < n; y++ {
+		for x, span := range spans {
+			o := (y*size + x) * 4
+			for i, w := range span.weights {
+				p := src.PixOffset(span.start+i, y)
+				for c := 0; c < 4; c++ {
+					rows[o+c] += w * float64(src.Pix[p+c])
+				}
+			}
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	for x := 0; x < size; x++ {
+		for y, span := range spans {
+			var sum [4]float64
+			for i, w := range span.weights {
+				o := ((span.start+i)*size + x) * 4
+				for c := 0; c < 4; c++ {
+					sum[c] += w * rows[o+c]
+				}
+			}
+			p := dst.PixOffset(x, y)
+			for c := 0; c < 4; c++ {
+				dst.Pix[p+c] = uint8(math.Min(math.Round(sum[c]), 255))
+			}
+		}
+	}
+	return dst
+}
+
+// boxSpan is the range of source pixels covered by a resized pixel,
+// starting at start, along with their weights.
+type boxSpan struct {
+	start   int
+	weights []float64
+}
+
+// boxSpans returns the spans of the size pixels resized from n >= size
+// pixels.
+func boxSpans(n, size int) []boxSpan {
+	scale := float64(n) / float64(size)
+	spans := make([]boxSpan, size)
+	for i := range spans {
+		lo, hi := float64(i)*scale, float64(i+1)*scale
+		start, end := int(lo), int(math.Ceil(hi))
+		if end > n {
+			end = n
+		}
+		span := boxSpan{start: start, weights: make([]float64, end-start)}
+		for j := start; j < end; j++ {
+			span.weights[j-start] = (math.Min(hi, float64(j+1)) - math.Max(lo, float64(j))) / scale
+		}
+		spans[i] = span
+	}
+	return spans
+}
@@ -0,0 +1,186 @@
+package onesignal
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// encodeTestImage returns a width x height PNG whose left half is opaque
+// red and right half transparent.
+func encodeTestImage(t *testing.T, width, height int) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width/2; x++ {
+			img.Set(x, y, color.NRGBA{R: 255, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func decodeTestIcon(t *testing.T, b []byte) image.Image {
+	img, err := png.Decode(bytes.NewReader(b))
+	if err != nil {
+		t.Fatalf("the icon is not a PNG: %v", err)
+	}
+	return img
+}
+
+func TestNewSafariIcons(t *testing.T) {
+	icons, err := NewSafariIcons(bytes.NewReader(encodeTestImage(t, 512, 512)))
+	if err != nil {
+		t.Fatalf("NewSafariIcons returned an error: %v", err)
+	}
+	if len(icons) != len(SafariIconSizes) {
+		t.Fatalf("NewSafariIcons returned %d icons, want %d", len(icons), len(SafariIconSizes))
+	}
+
+	for _, size := range SafariIconSizes {
+		img := decodeTestIcon(t, icons[size])
+		if got := img.Bounds(); got != image.Rect(0, 0, size, size) {
+			t.Errorf("icon %d has bounds %v", size, got)
+		}
+		if got := color.NRGBAModel.Convert(img.At(0, 0)); got != (color.NRGBA{R: 255, A: 255}) {
+			t.Errorf("icon %d has the left pixel %v, want opaque red", size, got)
+		}
+		if _, _, _, a := img.At(size-1, size-1).RGBA(); a != 0 {
+			t.Errorf("icon %d has the right pixel with alpha %d, want transparent", size, a)
+		}
+	}
+}
+
+func TestNewSafariIcons_uneven(t *testing.T) {
+	// 301 is not a multiple of the sizes: the edge between the halves, at
+	// 150*64/301 = 31.9 pixels, is blended, the rest keeps its color
+	icons, err := NewSafariIcons(bytes.NewReader(encodeTestImage(t, 301, 301)))
+	if err != nil {
+		t.Fatalf("NewSafariIcons returned an error: %v", err)
+	}
+	img := decodeTestIcon(t, icons[64])
+	if got := color.NRGBAModel.Convert(img.At(30, 10)); got != (color.NRGBA{R: 255, A: 255}) {
+		t.Errorf("pixel left of the edge is %v, want opaque red", got)
+	}
+	if got := color.NRGBAModel.Convert(img.At(31, 10)).(color.NRGBA); got.R != 255 || got.A == 0 || got.A == 255 {
+		t.Errorf("pixel on the edge is %v, want translucent red", got)
+	}
+}
+
+func TestNewSafariIcons_JPEG(t *testing.T) {
+	var buf bytes.Buffer
+	jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, 256, 256)), nil)
+
+	icons, err := NewSafariIcons(&buf)
+	if err != nil {
+		t.Fatalf("NewSafariIcons returned an error: %v", err)
+	}
+	if got := decodeTestIcon(t, icons[256]).Bounds(); got != image.Rect(0, 0, 256, 256) {
+		t.Errorf("icon 256 has bounds %v", got)
+	}
+}
+
+func TestNewSafariIcons_invalid(t *testing.T) {
+	tests := []struct {
+		image []byte
+		want  string
+	}{
+		{encodeTestImage(t, 512, 256), "must be square"},
+		{encodeTestImage(t, 128, 128), "at least 256x256"},
+		{[]byte("not an image"), "invalid icon image"},
+	}
+	for _, tt := range tests {
+		_, err := NewSafariIcons(bytes.NewReader(tt.image))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("NewSafariIcons returned %v, want an error containing %q", err, tt.want)
+		}
+	}
+}
+
+// hostIcons returns an upload function keeping the icons in hosted, and
+// returning their URL on example.com.
+func hostIcons(hosted map[string][]byte) func(int, []byte) (string, error) {
+	return func(size int, png []byte) (string, error) {
+		url := fmt.Sprintf("https://example.com/icons/%dx%d.png", size, size)
+		hosted[url] = png
+		return url, nil
+	}
+}
+
+func TestAppRequest_SetSafariIconsFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "icon.png")
+	if err := os.WriteFile(path, encodeTestImage(t, 256, 256), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	req := &AppRequest{}
+	hosted := make(map[string][]byte)
+	if err := req.SetSafariIconsFromFile(path, hostIcons(hosted)); err != nil {
+		t.Fatalf("SetSafariIconsFromFile returned an error: %v", err)
+	}
+	for size, url := range map[int]string{
+		16:  req.SafariIcon1616,
+		32:  req.SafariIcon3232,
+		64:  req.SafariIcon6464,
+		128: req.SafariIcon128128,
+		256: req.SafariIcon256256,
+	} {
+		if want := fmt.Sprintf("https://example.com/icons/%dx%d.png", size, size); url != want {
+			t.Errorf("icon %d is %q, want %q", size, url, want)
+			continue
+		}
+		if got := decodeTestIcon(t, hosted[url]).Bounds().Dx(); got != size {
+			t.Errorf("icon %d is %d pixels wide", size, got)
+		}
+	}
+
+	if err := req.SetSafariIconsFromFile(filepath.Join(t.TempDir(), "missing.png"), hostIcons(hosted)); err == nil {
+		t.Errorf("SetSafariIconsFromFile should have returned an error for a missing file")
+	}
+}
+
+func TestAppRequest_SetSafariIcons_partial(t *testing.T) {
+	req := &AppRequest{SafariIcon1616: "https://example.com/16x16.png"}
+	if err := req.SetSafariIcons(SafariIcons{256: []byte("png")}, hostIcons(map[string][]byte{})); err != nil {
+		t.Fatalf("SetSafariIcons returned an error: %v", err)
+	}
+
+	if req.SafariIcon1616 != "https://example.com/16x16.png" {
+		t.Errorf("SafariIcon1616 is %q, want it unchanged", req.SafariIcon1616)
+	}
+	if want := "https://example.com/icons/256x256.png"; req.SafariIcon256256 != want {
+		t.Errorf("SafariIcon256256 is %q, want %q", req.SafariIcon256256, want)
+	}
+}
+
+func TestAppRequest_SetSafariIcons_uploadError(t *testing.T) {
+	icons, err := NewSafariIcons(bytes.NewReader(encodeTestImage(t, 256, 256)))
+	if err != nil {
+		t.Fatalf("NewSafariIcons returned an error: %v", err)
+	}
+
+	req := &AppRequest{}
+	uploadErr := errors.New("upload failed")
+	err = req.SetSafariIcons(icons, func(size int, png []byte) (string, error) {
+		if size == 128 {
+			return "", uploadErr
+		}
+		return "https://example.com/icon.png", nil
+	})
+	if !errors.Is(err, uploadErr) {
+		t.Errorf("SetSafariIcons returned %v, want the upload error", err)
+	}
+	if *req != (AppRequest{}) {
+		t.Errorf("SetSafariIcons changed the request: %+v", req)
+	}
+}
@@ -1,6 +1,7 @@
 package onesignal
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"reflect"
@@ -113,6 +114,28 @@ func TestAppsService_List(t *testing.T) {
 	}
 }
 
+func TestAppsService_ListWithContext(t *testing.T) {
+	setup()
+	defer teardown()
+
+	requestSent := false
+
+	mux.HandleFunc("/apps", func(w http.ResponseWriter, r *http.Request) {
+		requestSent = true
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err := client.Apps.ListWithContext(ctx)
+	if err != context.Canceled {
+		t.Errorf("ListWithContext returned %v, want %v", err, context.Canceled)
+	}
+
+	if requestSent == true {
+		t.Errorf("Request should not have been sent")
+	}
+}
+
 func TestAppsService_Get(t *testing.T) {
 	setup()
 	defer teardown()
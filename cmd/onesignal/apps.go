@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tbalthazar/onesignal-go"
+	"github.com/tbalthazar/onesignal-go/appconfig"
+)
+
+var appsCommands = map[string]command{
+	"list":   {usage: "", run: (*cli).listApps},
+	"get":    {usage: "<app-id>", run: (*cli).getApp},
+	"create": {usage: "[-name name] [-f file]", run: (*cli).createApp},
+	"update": {usage: "<app-id> [-name name] [-f file]", run: (*cli).updateApp},
+	"plan":   {usage: "-f file [-write-only]", run: (*cli).planApps},
+	"apply":  {usage: "-f file [-write-only] [-yes]", run: (*cli).applyApps},
+}
+
+var appColumns = []string{"ID", "NAME", "PLAYERS", "MESSAGEABLE", "CREATED"}
+
+func appValues(app *onesignal.App) []string {
+	return []string{app.ID, app.Name, itoa(app.Players), itoa(app.MessagablePlayers), formatTime(app.CreatedAt)}
+}
+
+func (c *cli) listApps(args []string) error {
+	fs := c.flags("apps", "list")
+	if err := parse(fs, args, 0); err != nil {
+		return err
+	}
+
+	apps, _, err := c.client.Apps.List()
+	if err != nil {
+		return err
+	}
+	p := c.newPrinter(appColumns, true)
+	for i := range apps {
+		if err := p.print(apps[i], appValues(&apps[i])); err != nil {
+			return err
+		}
+	}
+	return p.close()
+}
+
+func (c *cli) getApp(args []string) error {
+	fs := c.flags("apps", "get")
+	if err := parse(fs, args, 1); err != nil {
+		return err
+	}
+
+	app, _, err := c.client.Apps.Get(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	return c.printOne(app, appColumns, appValues(app))
+}
+
+// appRequest parses the flags of the apps create and update commands into
+// an AppRequest.
+func (c *cli) appRequest(name string, args []string, nargs int) (*onesignal.AppRequest, []string, error) {
+	fs := c.flags("apps", name)
+	file := fs.String("f", "", "JSON or YAML `file` holding the app, - for the standard input")
+	appName := fs.String("name", "", "`name` of the app")
+	if err := parse(fs, args, nargs); err != nil {
+		return nil, nil, err
+	}
+
+	req := &onesignal.AppRequest{}
+	if err := c.readBody(*file, req); err != nil {
+		return nil, nil, err
+	}
+	if *appName != "" {
+		req.Name = *appName
+	}
+	return req, fs.Args(), nil
+}
+
+func (c *cli) createApp(args []string) error {
+	req, _, err := c.appRequest("create", args, 0)
+	if err != nil {
+		return err
+	}
+
+	app, _, err := c.client.Apps.Create(req)
+	if err != nil {
+		return err
+	}
+	return c.printOne(app, appColumns, appValues(app))
+}
+
+func (c *cli) updateApp(args []string) error {
+	req, rest, err := c.appRequest("update", args, 1)
+	if err != nil {
+		return err
+	}
+
+	app, _, err := c.client.Apps.Update(rest[0], req)
+	if err != nil {
+		return err
+	}
+	return c.printOne(app, appColumns, appValues(app))
+}
+
+// appsPlan parses the flags of the apps plan and apply commands, and
+// returns the plan of the config file they give.
+func (c *cli) appsPlan(name string, args []string, yes *bool) (*appconfig.Plan, error) {
+	fs := c.flags("apps", name)
+	file := fs.String("f", "", "JSON or YAML `file` holding the desired state of the apps")
+	writeOnly := fs.Bool("write-only", false, "also update the write-only fields, such as the APNs certificates")
+	if yes != nil {
+		fs.BoolVar(yes, "yes", false, "apply the plan without asking for confirmation")
+	}
+	if err := parse(fs, args, 0); err != nil {
+		return nil, err
+	}
+	if *file == "" {
+		fs.Usage()
+		return nil, errUsage
+	}
+
+	cfg, err := appconfig.Load(*file)
+	if err != nil {
+		return nil, err
+	}
+	return appconfig.NewPlan(context.Background(), c.client, cfg, appconfig.WithWriteOnly(*writeOnly))
+}
+
+func (c *cli) planApps(args []string) error {
+	plan, err := c.appsPlan("plan", args, nil)
+	if err != nil {
+		return err
+	}
+	_, err = plan.WriteTo(c.stdout)
+	return err
+}
+
+func (c *cli) applyApps(args []string) error {
+	var yes bool
+	plan, err := c.appsPlan("apply", args, &yes)
+	if err != nil {
+		return err
+	}
+
+	// the plan goes to stderr so that stdout only holds the applied apps,
+	// in the output format
+	if _, err := plan.WriteTo(c.stderr); err != nil {
+		return err
+	}
+	if !plan.HasChanges() {
+		return nil
+	}
+	if !yes {
+		fmt.Fprint(c.stderr, "\nApply these changes? Only yes is accepted: ")
+		answer, _ := bufio.NewReader(c.stdin).ReadString('\n')
+		if strings.TrimSpace(answer) != "yes" {
+			return errors.New("apply canceled")
+		}
+	}
+
+	apps, err := plan.Apply(context.Background(), c.client)
+	p := c.newPrinter(appColumns, true)
+	for i := range apps {
+		if err := p.print(apps[i], appValues(&apps[i])); err != nil {
+			return err
+		}
+	}
+	if closeErr := p.close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
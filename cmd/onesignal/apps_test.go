@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/tbalthazar/onesignal-go"
+)
+
+func TestApps(t *testing.T) {
+	srv, e := setup(t)
+
+	stdout, stderr, code := e.run("", "-output", "json", "apps", "create", "-name", "New app")
+	if code != 0 {
+		t.Fatalf("apps create exited with %d: %s", code, stderr)
+	}
+	var app onesignal.App
+	if err := json.Unmarshal([]byte(stdout), &app); err != nil {
+		t.Fatalf("apps create printed invalid JSON %q: %v", stdout, err)
+	}
+	if app.ID == "" || app.Name != "New app" {
+		t.Errorf("apps create printed %+v", app)
+	}
+
+	file := writeFile(t, "app.yaml", "name: From file\nsite_name: example\n")
+	if _, stderr, code := e.run("", "apps", "update", app.ID, "-f", file); code != 0 {
+		t.Fatalf("apps update exited with %d: %s", code, stderr)
+	}
+	if got := srv.Apps()[1]; got.Name != "From file" || got.SiteName != "example" {
+		t.Errorf("updated app is %+v", got)
+	}
+
+	stdout, _, _ = e.run("", "apps", "get", app.ID)
+	if lines := strings.Split(stdout, "\n"); len(lines) != 3 || !strings.HasPrefix(lines[0], "ID") || !strings.Contains(lines[1], "From file") {
+		t.Errorf("apps get printed %q", stdout)
+	}
+
+	stdout, _, _ = e.run("", "-output", "csv", "apps", "list")
+	if got, want := strings.Count(stdout, "\n"), 3; got != want {
+		t.Errorf("apps list printed %d lines, want %d: %q", got, want, stdout)
+	}
+}
+
+func TestApps_planAndApply(t *testing.T) {
+	srv, e := setup(t)
+	appID := e["ONESIGNAL_APP_ID"]
+	file := writeFile(t, "apps.yaml", `
+apps:
+  - id: `+appID+`
+    gcm_key: secret-gcm-key
+  - name: Staging
+`)
+
+	stdout, stderr, code := e.run("", "apps", "plan", "-f", file)
+	if code != 0 {
+		t.Fatalf("apps plan exited with %d: %s", code, stderr)
+	}
+	if !strings.Contains(stdout, "gcm_key: (sensitive)") || strings.Contains(stdout, "secret") || !strings.Contains(stdout, "1 to create, 1 to update") {
+		t.Errorf("apps plan printed %q", stdout)
+	}
+
+	// refused unless confirmed
+	if _, _, code := e.run("no\n", "apps", "apply", "-f", file); code != 1 || len(srv.Apps()) != 1 {
+		t.Errorf("apps apply exited with %d and left %d apps, want 1 and 1", code, len(srv.Apps()))
+	}
+
+	stdout, stderr, code = e.run("yes\n", "-output", "csv", "apps", "apply", "-f", file)
+	if code != 0 {
+		t.Fatalf("apps apply exited with %d: %s", code, stderr)
+	}
+	if got, want := strings.Count(stdout, "\n"), 3; got != want {
+		t.Errorf("apps apply printed %d lines, want %d: %q", got, want, stdout)
+	}
+	if apps := srv.Apps(); len(apps) != 2 || apps[0].GCMKey != "secret-gcm-key" || apps[1].Name != "Staging" {
+		t.Errorf("the apps are %+v", apps)
+	}
+
+	stdout, _, _ = e.run("", "apps", "plan", "-f", file)
+	if !strings.Contains(stdout, "0 to create, 0 to update, 2 unchanged") {
+		t.Errorf("apps plan printed %q once applied", stdout)
+	}
+	if _, stderr, code := e.run("", "apps", "apply", "-yes", "-f", file); code != 0 {
+		t.Errorf("apps apply exited with %d: %s", code, stderr)
+	}
+}
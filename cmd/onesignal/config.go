@@ -0,0 +1,89 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/tbalthazar/onesignal-go"
+	"gopkg.in/yaml.v3"
+)
+
+// config is the content of the config file.
+type config struct {
+	AppKey  string `yaml:"app_key"`
+	UserKey string `yaml:"user_key"`
+	AppID   string `yaml:"app_id"`
+	APIURL  string `yaml:"api_url"`
+}
+
+// loadConfig reads the config file at path. A missing file is only an error
+// if required.
+func loadConfig(path string, required bool) (*config, error) {
+	conf := &config{}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && !required {
+		return conf, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(b, conf); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %v", path, err)
+	}
+	return conf, nil
+}
+
+// configure creates the client of c from the environment and the config
+// file, the environment taking precedence. The app ID is only read from
+// them if not already set.
+func (c *cli) configure(configPath string) error {
+	required := configPath != ""
+	if configPath == "" {
+		configPath = c.getenv("ONESIGNAL_CONFIG")
+		required = configPath != ""
+	}
+	if configPath == "" {
+		dir, err := os.UserConfigDir()
+		if err == nil {
+			configPath = filepath.Join(dir, "onesignal", "config.yaml")
+		}
+	}
+
+	conf := &config{}
+	if configPath != "" {
+		var err error
+		if conf, err = loadConfig(configPath, required); err != nil {
+			return err
+		}
+	}
+
+	var opts []onesignal.Option
+	setting := func(env, value string) string {
+		if v := c.getenv(env); v != "" {
+			return v
+		}
+		return value
+	}
+	if key := setting("ONESIGNAL_APP_KEY", conf.AppKey); key != "" {
+		opts = append(opts, onesignal.WithAppKey(key))
+	}
+	if key := setting("ONESIGNAL_USER_KEY", conf.UserKey); key != "" {
+		opts = append(opts, onesignal.WithUserKey(key))
+	}
+	if rawurl := setting("ONESIGNAL_API_URL", conf.APIURL); rawurl != "" {
+		opts = append(opts, onesignal.WithBaseURL(rawurl))
+	}
+	if c.appID == "" {
+		c.appID = setting("ONESIGNAL_APP_ID", conf.AppID)
+	}
+
+	client, err := onesignal.NewClient(opts...)
+	if err != nil {
+		return err
+	}
+	c.client = client
+	return nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tbalthazar/onesignal-go"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestConfigure_file(t *testing.T) {
+	srv, e := setup(t)
+	appID := e["ONESIGNAL_APP_ID"]
+	path := writeFile(t, "config.yaml", "app_key: "+srv.AppKey+"\nuser_key: "+srv.UserKey+"\napp_id: "+appID+"\napi_url: "+srv.URL+"\n")
+
+	for _, e := range []env{
+		{},
+		{"ONESIGNAL_CONFIG": path},
+	} {
+		args := []string{"-output", "csv", "notifications", "list"}
+		if len(e) == 0 {
+			args = append([]string{"-config", path}, args...)
+		}
+		stdout, stderr, code := e.run("", args...)
+		if code != 0 {
+			t.Fatalf("exited with %d: %s", code, stderr)
+		}
+		if !strings.HasPrefix(stdout, "ID,SUCCESSFUL") {
+			t.Errorf("printed %q", stdout)
+		}
+	}
+}
+
+func TestConfigure_envTakesPrecedence(t *testing.T) {
+	srv, e := setup(t)
+	e["ONESIGNAL_CONFIG"] = writeFile(t, "config.yaml", "app_key: wrong-key\napi_url: http://invalid.example.com\n")
+
+	_, stderr, code := e.run("", "players", "list")
+	if code != 0 {
+		t.Errorf("exited with %d: %s", code, stderr)
+	}
+
+	// the -app-id flag takes precedence over the environment
+	otherAppID := srv.AddApp(onesignal.App{Name: "Other app"})
+	srv.AddPlayer(otherAppID, onesignal.Player{ID: "other-player"})
+	stdout, _, _ := e.run("", "-app-id", otherAppID, "players", "list")
+	if !strings.Contains(stdout, "other-player") {
+		t.Errorf("printed %q, want the players of %v", stdout, otherAppID)
+	}
+}
+
+func TestConfigure_missingFile(t *testing.T) {
+	_, e := setup(t)
+
+	_, stderr, code := e.run("", "-config", filepath.Join(t.TempDir(), "missing.yaml"), "apps", "list")
+	if code != 1 || !strings.Contains(stderr, "missing.yaml") {
+		t.Errorf("exited with %d and printed %q, want a missing file error", code, stderr)
+	}
+
+	_, stderr, code = e.run("", "-config", writeFile(t, "invalid.yaml", "app_key: [\n"), "apps", "list")
+	if code != 1 || !strings.Contains(stderr, "invalid config file") {
+		t.Errorf("exited with %d and printed %q, want an invalid file error", code, stderr)
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/tbalthazar/onesignal-go/appconfig"
+)
+
+// readBody decodes the JSON or YAML file at path, or the standard input if
+// path is "-", into v, using the JSON field names of v. Nothing is read if
+// path is empty.
+func (c *cli) readBody(path string, v interface{}) error {
+	if path == "" {
+		return nil
+	}
+
+	var b []byte
+	var err error
+	if path == "-" {
+		b, err = io.ReadAll(c.stdin)
+	} else {
+		b, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return err
+	}
+
+	j, err := appconfig.YAMLToJSON(b)
+	if err != nil {
+		return fmt.Errorf("invalid request body %s: %v", path, err)
+	}
+	if err := json.Unmarshal(j, v); err != nil {
+		return fmt.Errorf("invalid request body %s: %v", path, err)
+	}
+	return nil
+}
+
+// stringsFlag is a flag that can be repeated.
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringsFlag) Set(v string) error {
+	*f = append(*f, v)
+	return nil
+}
+
+// tagsFlag is a key=value flag that can be repeated.
+type tagsFlag map[string]string
+
+func (f tagsFlag) String() string {
+	var pairs []string
+	for k, v := range f {
+		pairs = append(pairs, k+"="+v)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (f tagsFlag) Set(v string) error {
+	key, value, ok := strings.Cut(v, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("%q is not key=value", v)
+	}
+	f[key] = value
+	return nil
+}
+
+// splitList splits a comma-separated list, ignoring empty items.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tbalthazar/onesignal-go"
+)
+
+func TestReadBody(t *testing.T) {
+	yamlFile := writeFile(t, "notification.yaml", `
+app_id: id123
+contents:
+  en: Hello
+included_segments:
+  - Active Users
+data:
+  count: 2
+`)
+	jsonFile := writeFile(t, "notification.json", `{"app_id": "id123", "contents": {"en": "Hello"}, "included_segments": ["Active Users"], "data": {"count": 2}}`)
+	want := &onesignal.NotificationRequest{
+		AppID:            "id123",
+		Contents:         map[string]string{"en": "Hello"},
+		IncludedSegments: []string{"Active Users"},
+		Data:             map[string]interface{}{"count": float64(2)},
+	}
+
+	for _, path := range []string{yamlFile, jsonFile, "-"} {
+		c := &cli{stdin: strings.NewReader(`{"app_id": "id123", "contents": {"en": "Hello"}, "included_segments": ["Active Users"], "data": {"count": 2}}`)}
+		got := &onesignal.NotificationRequest{}
+		if err := c.readBody(path, got); err != nil {
+			t.Fatalf("readBody(%v) returned an error: %v", path, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("readBody(%v) decoded %+v, want %+v", path, got, want)
+		}
+	}
+}
+
+func TestReadBody_invalid(t *testing.T) {
+	c := &cli{}
+	for _, content := range []string{"contents: [\n", "contents: [a, b]\n"} {
+		if err := c.readBody(writeFile(t, "invalid.yaml", content), &onesignal.NotificationRequest{}); err == nil {
+			t.Errorf("readBody(%q) should have returned an error", content)
+		}
+	}
+}
+
+func TestTagsFlag(t *testing.T) {
+	tags := tagsFlag{}
+	for _, v := range []string{"level=5", "premium=", "url=a=b"} {
+		if err := tags.Set(v); err != nil {
+			t.Errorf("Set(%q) returned an error: %v", v, err)
+		}
+	}
+	if got, want := tags, (tagsFlag{"level": "5", "premium": "", "url": "a=b"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("tags are %v, want %v", got, want)
+	}
+	for _, v := range []string{"level", "=5"} {
+		if err := tags.Set(v); err == nil {
+			t.Errorf("Set(%q) should have returned an error", v)
+		}
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	if got, want := splitList(" country, ,external_user_id"), []string{"country", "external_user_id"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("splitList returned %v, want %v", got, want)
+	}
+	if got := splitList(""); got != nil {
+		t.Errorf("splitList returned %v, want nil", got)
+	}
+}
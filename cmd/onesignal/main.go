@@ -0,0 +1,213 @@
+// Command onesignal manages the apps, players and notifications of OneSignal
+// from the command line.
+//
+// Usage:
+//
+//	onesignal [-config file] [-app-id id] [-output table|json|csv] <group> <command> [flags] [args]
+//
+// The commands are:
+//
+//	apps list
+//	apps get <app-id>
+//	apps create [-name name] [-f file]
+//	apps update <app-id> [-name name] [-f file]
+//	apps plan -f file [-write-only]
+//	apps apply -f file [-write-only] [-yes]
+//	players list [-limit n] [-offset n] [-all]
+//	players get <player-id>
+//	players create [-device-type n] [-identifier id] [-tag key=value]... [-f file]
+//	players update <player-id> [-tag key=value]... [-f file]
+//	players export [-segment name] [-extra-fields a,b] [-last-active-since time]
+//	notifications send [-message text] [-heading text] [-player id]... [-segment name]... [-url url] [-f file]
+//	notifications get <notification-id>
+//	notifications list [-limit n] [-offset n]
+//	notifications cancel <notification-id>
+//	notifications track-open <notification-id>
+//
+// The keys, the app ID and the API URL are read from the ONESIGNAL_APP_KEY,
+// ONESIGNAL_USER_KEY, ONESIGNAL_APP_ID and ONESIGNAL_API_URL environment
+// variables, or else from a YAML config file:
+//
+//	app_key: YourOneSignalAppKey
+//	user_key: YourOneSignalUserKey
+//	app_id: YourAppID
+//
+// The config file is given with -config or ONESIGNAL_CONFIG, and defaults to
+// onesignal/config.yaml in the user config directory, such as
+// ~/.config/onesignal/config.yaml on Linux.
+//
+// Request bodies are read from the JSON or YAML file given with -f, or from
+// the standard input if the file is "-", using the field names of the
+// OneSignal API. Flags take precedence over the file:
+//
+//	onesignal notifications send -f notification.yaml -segment "Active Users"
+//
+// The apps plan and apply commands manage apps declaratively from a file
+// holding their desired state, in the format of the appconfig package. The
+// plan prints the changes to make, with the secrets masked, and apply makes
+// them once confirmed:
+//
+//	onesignal apps plan -f apps.yaml
+//	onesignal apps apply -f apps.yaml
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/tbalthazar/onesignal-go"
+)
+
+// cli holds the state of a run of the command.
+type cli struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	getenv func(string) string
+
+	client *onesignal.Client
+	appID  string
+	output string
+}
+
+// command is a subcommand, such as "apps list".
+type command struct {
+	usage string
+	run   func(c *cli, args []string) error
+}
+
+// commands are the subcommands by group and name.
+var commands map[string]map[string]command
+
+func init() {
+	// commands is set in init since the commands refer to it for their
+	// usage
+	commands = map[string]map[string]command{
+		"apps":          appsCommands,
+		"players":       playersCommands,
+		"notifications": notificationsCommands,
+	}
+}
+
+// errUsage is returned by the commands called with invalid arguments, once
+// the usage has been printed.
+var errUsage = errors.New("invalid usage")
+
+func main() {
+	c := &cli{
+		stdin:  os.Stdin,
+		stdout: os.Stdout,
+		stderr: os.Stderr,
+		getenv: os.Getenv,
+	}
+	os.Exit(c.run(os.Args[1:]))
+}
+
+// run runs the command with args, and returns the exit code.
+func (c *cli) run(args []string) int {
+	fs := flag.NewFlagSet("onesignal", flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	fs.Usage = c.usage
+	configPath := fs.String("config", "", "YAML config `file` holding the keys")
+	fs.StringVar(&c.appID, "app-id", "", "app `id`, overriding ONESIGNAL_APP_ID")
+	fs.StringVar(&c.output, "output", "table", "output `format`: table, json or csv")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if c.output != "table" && c.output != "json" && c.output != "csv" {
+		fmt.Fprintf(c.stderr, "onesignal: invalid output format %q\n", c.output)
+		return 2
+	}
+
+	args = fs.Args()
+	if len(args) < 2 {
+		c.usage()
+		return 2
+	}
+	cmd, ok := commands[args[0]][args[1]]
+	if !ok {
+		fmt.Fprintf(c.stderr, "onesignal: unknown command %q\n", strings.Join(args[:2], " "))
+		c.usage()
+		return 2
+	}
+
+	if err := c.configure(*configPath); err != nil {
+		fmt.Fprintf(c.stderr, "onesignal: %v\n", err)
+		return 1
+	}
+	if err := cmd.run(c, args[2:]); err != nil {
+		if err == errUsage {
+			return 2
+		}
+		fmt.Fprintf(c.stderr, "onesignal: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func (c *cli) usage() {
+	fmt.Fprintln(c.stderr, "usage: onesignal [-config file] [-app-id id] [-output table|json|csv] <group> <command> [flags] [args]")
+	fmt.Fprintln(c.stderr, "\ncommands:")
+	groups := make([]string, 0, len(commands))
+	for group := range commands {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+	for _, group := range groups {
+		names := make([]string, 0, len(commands[group]))
+		for name := range commands[group] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(c.stderr, "  %s %s %s\n", group, name, commands[group][name].usage)
+		}
+	}
+}
+
+// flags returns a flag set for the command "group name".
+func (c *cli) flags(group, name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(group+" "+name, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(c.stderr, "usage: onesignal %s %s %s\n", group, name, commands[group][name].usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parse parses args with fs, requiring nargs positional arguments. Unlike
+// fs.Parse, flags may follow the positional arguments, which are then
+// returned by fs.Args.
+func parse(fs *flag.FlagSet, args []string, nargs int) error {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return errUsage
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	if len(positional) != nargs {
+		fs.Usage()
+		return errUsage
+	}
+	fs.Parse(append([]string{"--"}, positional...))
+	return nil
+}
+
+// requireAppID returns the app ID, or an error if none is configured.
+func (c *cli) requireAppID() (string, error) {
+	if c.appID == "" {
+		return "", errors.New("no app ID: set ONESIGNAL_APP_ID, app_id in the config file or -app-id")
+	}
+	return c.appID, nil
+}
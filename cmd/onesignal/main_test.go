@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/tbalthazar/onesignal-go"
+	"github.com/tbalthazar/onesignal-go/onesignaltest"
+)
+
+// env is the environment of a test run.
+type env map[string]string
+
+// setup starts a fake OneSignal server with an app, and returns it along
+// with the environment pointing to it.
+func setup(t *testing.T) (*onesignaltest.Server, env) {
+	// keep the config file of the user out of the tests
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	srv := onesignaltest.NewServer()
+	t.Cleanup(srv.Close)
+	appID := srv.AddApp(onesignal.App{Name: "Test app"})
+
+	return srv, env{
+		"ONESIGNAL_APP_KEY":  srv.AppKey,
+		"ONESIGNAL_USER_KEY": srv.UserKey,
+		"ONESIGNAL_API_URL":  srv.URL,
+		"ONESIGNAL_APP_ID":   appID,
+	}
+}
+
+// run runs the command with args, and returns its output and exit code.
+func (e env) run(stdin string, args ...string) (stdout, stderr string, code int) {
+	var out, errOut bytes.Buffer
+	c := &cli{
+		stdin:  strings.NewReader(stdin),
+		stdout: &out,
+		stderr: &errOut,
+		getenv: func(key string) string { return e[key] },
+	}
+	code = c.run(args)
+	return out.String(), errOut.String(), code
+}
+
+func TestRun_usage(t *testing.T) {
+	_, e := setup(t)
+
+	for _, args := range [][]string{
+		{},
+		{"apps"},
+		{"apps", "unknown"},
+		{"-output", "xml", "apps", "list"},
+		{"apps", "get"},
+		{"apps", "get", "id1", "id2"},
+		{"players", "list", "-unknown"},
+	} {
+		_, stderr, code := e.run("", args...)
+		if code != 2 {
+			t.Errorf("run(%q) exited with %d, want 2", args, code)
+		}
+		if !strings.Contains(stderr, "usage:") && !strings.Contains(stderr, "invalid output format") {
+			t.Errorf("run(%q) printed %q, want the usage", args, stderr)
+		}
+	}
+}
+
+func TestRun_error(t *testing.T) {
+	_, e := setup(t)
+
+	_, stderr, code := e.run("", "players", "get", "unknown")
+	if code != 1 {
+		t.Errorf("exited with %d, want 1", code)
+	}
+	if !strings.HasPrefix(stderr, "onesignal: ") || !strings.Contains(stderr, "404") {
+		t.Errorf("printed %q, want a not found error", stderr)
+	}
+}
+
+func TestRun_noAppID(t *testing.T) {
+	_, e := setup(t)
+	delete(e, "ONESIGNAL_APP_ID")
+
+	_, stderr, code := e.run("", "notifications", "list")
+	if code != 1 || !strings.Contains(stderr, "no app ID") {
+		t.Errorf("exited with %d and printed %q, want a missing app ID error", code, stderr)
+	}
+}
+
+func TestParse_interleaved(t *testing.T) {
+	c := &cli{stderr: new(bytes.Buffer)}
+	fs := c.flags("players", "update")
+	tags := tagsFlag{}
+	fs.Var(tags, "tag", "")
+
+	if err := parse(fs, []string{"-tag", "a=1", "player1", "-tag", "b=2"}, 1); err != nil {
+		t.Fatalf("parse returned an error: %v", err)
+	}
+	if got, want := fs.Arg(0), "player1"; got != want {
+		t.Errorf("Arg(0) is %v, want %v", got, want)
+	}
+	if got, want := tags.String(), "a=1,b=2"; got != want && got != "b=2,a=1" {
+		t.Errorf("tags are %v, want %v", got, want)
+	}
+}
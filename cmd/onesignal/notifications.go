@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/tbalthazar/onesignal-go"
+)
+
+var notificationsCommands = map[string]command{
+	"send":       {usage: "[-message text] [-heading text] [-player id]... [-segment name]... [-url url] [-f file]", run: (*cli).sendNotification},
+	"get":        {usage: "<notification-id>", run: (*cli).getNotification},
+	"list":       {usage: "[-limit n] [-offset n]", run: (*cli).listNotifications},
+	"cancel":     {usage: "<notification-id>", run: (*cli).cancelNotification},
+	"track-open": {usage: "<notification-id>", run: (*cli).trackOpen},
+}
+
+var notificationColumns = []string{"ID", "SUCCESSFUL", "FAILED", "CONVERTED", "REMAINING", "QUEUED AT", "CANCELED", "CONTENTS"}
+
+func notificationValues(n *onesignal.Notification) []string {
+	return []string{
+		n.ID, itoa(n.Successful), itoa(n.Failed), itoa(n.Converted), itoa(n.Remaining),
+		formatTime(n.QueuedAt), fmt.Sprint(n.Canceled), n.Contents["en"],
+	}
+}
+
+func (c *cli) sendNotification(args []string) error {
+	fs := c.flags("notifications", "send")
+	file := fs.String("f", "", "JSON or YAML `file` holding the notification, - for the standard input")
+	message := fs.String("message", "", "English `text` of the notification")
+	heading := fs.String("heading", "", "English `title` of the notification")
+	url := fs.String("url", "", "`url` opened when the notification is clicked")
+	var players, segments stringsFlag
+	fs.Var(&players, "player", "player `id` to send the notification to, can be repeated")
+	fs.Var(&segments, "segment", "segment `name` to send the notification to, can be repeated")
+	if err := parse(fs, args, 0); err != nil {
+		return err
+	}
+
+	req := &onesignal.NotificationRequest{AppID: c.appID}
+	if err := c.readBody(*file, req); err != nil {
+		return err
+	}
+	if *message != "" {
+		req.Contents = withEnglish(req.Contents, *message)
+	}
+	if *heading != "" {
+		req.Headings = withEnglish(req.Headings, *heading)
+	}
+	if *url != "" {
+		req.URL = *url
+	}
+	if len(players) > 0 {
+		req.IncludePlayerIDs = players
+	}
+	if len(segments) > 0 {
+		req.IncludedSegments = segments
+	}
+
+	createRes, _, err := c.client.Notifications.Create(req)
+	if err != nil {
+		return err
+	}
+	if err := createRes.Err(); err != nil {
+		return err
+	}
+	return c.printOne(createRes, []string{"ID", "RECIPIENTS"}, []string{createRes.ID, itoa(createRes.Recipients)})
+}
+
+func (c *cli) getNotification(args []string) error {
+	fs := c.flags("notifications", "get")
+	if err := parse(fs, args, 1); err != nil {
+		return err
+	}
+	appID, err := c.requireAppID()
+	if err != nil {
+		return err
+	}
+
+	n, _, err := c.client.Notifications.Get(fs.Arg(0), &onesignal.NotificationGetOptions{AppID: appID})
+	if err != nil {
+		return err
+	}
+	return c.printOne(n, notificationColumns, notificationValues(n))
+}
+
+func (c *cli) listNotifications(args []string) error {
+	fs := c.flags("notifications", "list")
+	limit := fs.Int("limit", 50, "maximum `number` of notifications to list")
+	offset := fs.Int("offset", 0, "`number` of notifications to skip")
+	if err := parse(fs, args, 0); err != nil {
+		return err
+	}
+	appID, err := c.requireAppID()
+	if err != nil {
+		return err
+	}
+
+	listRes, _, err := c.client.Notifications.List(&onesignal.NotificationListOptions{AppID: appID, Limit: *limit, Offset: *offset})
+	if err != nil {
+		return err
+	}
+	p := c.newPrinter(notificationColumns, true)
+	for i := range listRes.Notifications {
+		if err := p.print(listRes.Notifications[i], notificationValues(&listRes.Notifications[i])); err != nil {
+			return err
+		}
+	}
+	return p.close()
+}
+
+func (c *cli) cancelNotification(args []string) error {
+	fs := c.flags("notifications", "cancel")
+	if err := parse(fs, args, 1); err != nil {
+		return err
+	}
+	appID, err := c.requireAppID()
+	if err != nil {
+		return err
+	}
+
+	successRes, _, err := c.client.Notifications.Delete(fs.Arg(0), &onesignal.NotificationDeleteOptions{AppID: appID})
+	if err != nil {
+		return err
+	}
+	return c.printOne(successRes, []string{"SUCCESS"}, []string{fmt.Sprint(successRes.Success)})
+}
+
+func (c *cli) trackOpen(args []string) error {
+	fs := c.flags("notifications", "track-open")
+	if err := parse(fs, args, 1); err != nil {
+		return err
+	}
+	appID, err := c.requireAppID()
+	if err != nil {
+		return err
+	}
+
+	successRes, _, err := c.client.Notifications.Update(fs.Arg(0), &onesignal.NotificationUpdateOptions{AppID: appID, Opened: true})
+	if err != nil {
+		return err
+	}
+	return c.printOne(successRes, []string{"SUCCESS"}, []string{fmt.Sprint(successRes.Success)})
+}
+
+// withEnglish returns m with its English value set to s.
+func withEnglish(m map[string]string, s string) map[string]string {
+	if m == nil {
+		m = make(map[string]string)
+	}
+	m["en"] = s
+	return m
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/csv"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tbalthazar/onesignal-go"
+)
+
+func TestNotifications(t *testing.T) {
+	srv, e := setup(t)
+	appID := e["ONESIGNAL_APP_ID"]
+	playerID := srv.AddPlayer(appID, onesignal.Player{})
+
+	file := writeFile(t, "notification.yaml", "contents:\n  en: From file\n  fr: Depuis le fichier\nurl: https://example.com\n")
+	stdout, stderr, code := e.run("", "-output", "csv", "notifications", "send", "-f", file, "-message", "Hello", "-player", playerID)
+	if code != 0 {
+		t.Fatalf("notifications send exited with %d: %s", code, stderr)
+	}
+	rows, _ := csv.NewReader(strings.NewReader(stdout)).ReadAll()
+	if len(rows) != 2 || rows[1][1] != "1" {
+		t.Fatalf("notifications send printed %q", rows)
+	}
+	notificationID := rows[1][0]
+
+	req, _ := srv.NotificationRequest(notificationID)
+	if got, want := req.Contents, map[string]string{"en": "Hello", "fr": "Depuis le fichier"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("contents are %v, want %v", got, want)
+	}
+	if req.URL != "https://example.com" || !reflect.DeepEqual(req.IncludePlayerIDs, []string{playerID}) {
+		t.Errorf("request is %+v", req)
+	}
+
+	for _, cmd := range []string{"track-open", "cancel"} {
+		if _, stderr, code := e.run("", "notifications", cmd, notificationID); code != 0 {
+			t.Fatalf("notifications %s exited with %d: %s", cmd, code, stderr)
+		}
+	}
+	stdout, _, _ = e.run("", "-output", "csv", "notifications", "get", notificationID)
+	rows, _ = csv.NewReader(strings.NewReader(stdout)).ReadAll()
+	if len(rows) != 2 || rows[1][3] != "1" || rows[1][6] != "true" || rows[1][7] != "Hello" {
+		t.Errorf("notifications get printed %q", rows)
+	}
+
+	stdout, _, _ = e.run("", "-output", "csv", "notifications", "list", "-limit", "10")
+	if got, want := strings.Count(stdout, "\n"), 2; got != want {
+		t.Errorf("notifications list printed %d lines, want %d: %q", got, want, stdout)
+	}
+}
+
+func TestNotifications_sendErrors(t *testing.T) {
+	_, e := setup(t)
+
+	// rejected by the validation of the client
+	_, stderr, code := e.run("", "notifications", "send", "-segment", "All")
+	if code != 1 || !strings.Contains(stderr, "contents") {
+		t.Errorf("exited with %d and printed %q, want a validation error", code, stderr)
+	}
+
+	// reported by OneSignal
+	_, stderr, code = e.run("", "notifications", "send", "-message", "Hello", "-player", "unknown")
+	if code != 1 || !strings.Contains(stderr, "unknown") {
+		t.Errorf("exited with %d and printed %q, want an invalid player IDs error", code, stderr)
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// printer prints items as a table, JSON or CSV.
+type printer interface {
+	// print prints an item: v in JSON, or its values in the columns of
+	// the table or CSV.
+	print(v interface{}, values []string) error
+
+	// close flushes the output.
+	close() error
+}
+
+// newPrinter returns a printer to stdout for the output format of c, with
+// columns. A list is printed as a JSON array, a single item as an object.
+func (c *cli) newPrinter(columns []string, list bool) printer {
+	switch c.output {
+	case "json":
+		return &jsonPrinter{w: c.stdout, list: list}
+	case "csv":
+		w := csv.NewWriter(c.stdout)
+		w.Write(columns)
+		return &csvPrinter{w: w}
+	default:
+		w := tabwriter.NewWriter(c.stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, strings.Join(columns, "\t"))
+		return &tablePrinter{w: w}
+	}
+}
+
+// printOne prints a single item.
+func (c *cli) printOne(v interface{}, columns, values []string) error {
+	p := c.newPrinter(columns, false)
+	if err := p.print(v, values); err != nil {
+		return err
+	}
+	return p.close()
+}
+
+type tablePrinter struct {
+	w *tabwriter.Writer
+}
+
+func (p *tablePrinter) print(v interface{}, values []string) error {
+	_, err := fmt.Fprintln(p.w, strings.Join(values, "\t"))
+	return err
+}
+
+func (p *tablePrinter) close() error {
+	return p.w.Flush()
+}
+
+type csvPrinter struct {
+	w *csv.Writer
+}
+
+func (p *csvPrinter) print(v interface{}, values []string) error {
+	return p.w.Write(values)
+}
+
+func (p *csvPrinter) close() error {
+	p.w.Flush()
+	return p.w.Error()
+}
+
+// jsonPrinter prints the items as they come, so that long lists such as
+// players exports are streamed.
+type jsonPrinter struct {
+	w    io.Writer
+	list bool
+	n    int
+}
+
+func (p *jsonPrinter) print(v interface{}, values []string) error {
+	if !p.list {
+		b, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(p.w, "%s\n", b)
+		return err
+	}
+
+	b, err := json.MarshalIndent(v, "  ", "  ")
+	if err != nil {
+		return err
+	}
+	sep := ",\n  "
+	if p.n == 0 {
+		sep = "[\n  "
+	}
+	p.n++
+	_, err = fmt.Fprintf(p.w, "%s%s", sep, b)
+	return err
+}
+
+func (p *jsonPrinter) close() error {
+	if !p.list {
+		return nil
+	}
+	if p.n == 0 {
+		_, err := fmt.Fprintln(p.w, "[]")
+		return err
+	}
+	_, err := fmt.Fprintln(p.w, "\n]")
+	return err
+}
+
+// formatUnix formats a Unix timestamp, or returns an empty string for 0.
+func formatUnix(secs int) string {
+	if secs == 0 {
+		return ""
+	}
+	return formatTime(time.Unix(int64(secs), 0))
+}
+
+// formatTime formats t in UTC, or returns an empty string for the zero time.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// formatMap formats m as key=value pairs sorted by key.
+func formatMap(m map[string]string) string {
+	pairs := make([]string, 0, len(m))
+	for k, v := range m {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, " ")
+}
+
+func itoa(i int) string {
+	return strconv.Itoa(i)
+}
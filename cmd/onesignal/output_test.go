@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+type item struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func printItems(output string, list bool, items ...item) string {
+	var out bytes.Buffer
+	c := &cli{stdout: &out, output: output}
+	p := c.newPrinter([]string{"NAME", "COUNT"}, list)
+	for _, it := range items {
+		p.print(it, []string{it.Name, itoa(it.Count)})
+	}
+	p.close()
+	return out.String()
+}
+
+func TestPrinter(t *testing.T) {
+	items := []item{{"first", 1}, {"second, with a comma", 22}}
+
+	tests := []struct {
+		output string
+		list   bool
+		items  []item
+		want   string
+	}{
+		{"table", true, items, "NAME                  COUNT\nfirst                 1\nsecond, with a comma  22\n"},
+		{"csv", true, items, "NAME,COUNT\nfirst,1\n\"second, with a comma\",22\n"},
+		{"json", true, items, "[\n  {\n    \"name\": \"first\",\n    \"count\": 1\n  },\n  {\n    \"name\": \"second, with a comma\",\n    \"count\": 22\n  }\n]\n"},
+		{"json", true, nil, "[]\n"},
+		{"json", false, items[:1], "{\n  \"name\": \"first\",\n  \"count\": 1\n}\n"},
+	}
+	for _, tt := range tests {
+		if got := printItems(tt.output, tt.list, tt.items...); got != tt.want {
+			t.Errorf("printed %q in %v, want %q", got, tt.output, tt.want)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	if got, want := formatUnix(1396326002), "2014-04-01T04:20:02Z"; got != want {
+		t.Errorf("formatUnix returned %v, want %v", got, want)
+	}
+	if got := formatUnix(0); got != "" {
+		t.Errorf("formatUnix returned %q, want an empty string", got)
+	}
+	if got := formatTime(time.Time{}); got != "" {
+		t.Errorf("formatTime returned %q, want an empty string", got)
+	}
+	if got, want := formatMap(map[string]string{"b": "2", "a": "1"}), "a=1 b=2"; got != want {
+		t.Errorf("formatMap returned %v, want %v", got, want)
+	}
+}
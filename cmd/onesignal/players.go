@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/tbalthazar/onesignal-go"
+)
+
+var playersCommands = map[string]command{
+	"list":   {usage: "[-limit n] [-offset n] [-all]", run: (*cli).listPlayers},
+	"get":    {usage: "<player-id>", run: (*cli).getPlayer},
+	"create": {usage: "[-device-type n] [-identifier id] [-tag key=value]... [-f file]", run: (*cli).createPlayer},
+	"update": {usage: "<player-id> [-tag key=value]... [-f file]", run: (*cli).updatePlayer},
+	"export": {usage: "[-segment name] [-extra-fields a,b] [-last-active-since time]", run: (*cli).exportPlayers},
+}
+
+var playerColumns = []string{"ID", "DEVICE TYPE", "IDENTIFIER", "LANGUAGE", "SESSIONS", "LAST ACTIVE", "TAGS"}
+
+func playerValues(p *onesignal.Player) []string {
+	return []string{p.ID, itoa(p.DeviceType), p.Identifier, p.Language, itoa(p.SessionCount), formatUnix(p.LastActive), formatMap(p.Tags)}
+}
+
+func (c *cli) listPlayers(args []string) error {
+	fs := c.flags("players", "list")
+	limit := fs.Int("limit", 50, "maximum `number` of players to list")
+	offset := fs.Int("offset", 0, "`number` of players to skip")
+	all := fs.Bool("all", false, "list all the players, page by page")
+	if err := parse(fs, args, 0); err != nil {
+		return err
+	}
+	appID, err := c.requireAppID()
+	if err != nil {
+		return err
+	}
+
+	p := c.newPrinter(playerColumns, true)
+	if *all {
+		for player, err := range c.client.Players.All(context.Background(), appID, nil) {
+			if err != nil {
+				return err
+			}
+			if err := p.print(player, playerValues(&player)); err != nil {
+				return err
+			}
+		}
+		return p.close()
+	}
+
+	listRes, _, err := c.client.Players.List(&onesignal.PlayerListOptions{AppID: appID, Limit: *limit, Offset: *offset})
+	if err != nil {
+		return err
+	}
+	for i := range listRes.Players {
+		if err := p.print(listRes.Players[i], playerValues(&listRes.Players[i])); err != nil {
+			return err
+		}
+	}
+	return p.close()
+}
+
+func (c *cli) getPlayer(args []string) error {
+	fs := c.flags("players", "get")
+	if err := parse(fs, args, 1); err != nil {
+		return err
+	}
+
+	player, _, err := c.client.Players.Get(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	return c.printOne(player, playerColumns, playerValues(player))
+}
+
+func (c *cli) createPlayer(args []string) error {
+	fs := c.flags("players", "create")
+	file := fs.String("f", "", "JSON or YAML `file` holding the player, - for the standard input")
+	deviceType := fs.Int("device-type", -1, "device `type`, such as 0 for iOS or 1 for Android")
+	identifier := fs.String("identifier", "", "push token `id` of the device")
+	tags := tagsFlag{}
+	fs.Var(tags, "tag", "`key=value` tag, can be repeated")
+	if err := parse(fs, args, 0); err != nil {
+		return err
+	}
+
+	req := &onesignal.PlayerRequest{AppID: c.appID}
+	if err := c.readBody(*file, req); err != nil {
+		return err
+	}
+	if *deviceType >= 0 {
+		req.DeviceType = *deviceType
+	}
+	if *identifier != "" {
+		req.Identifier = *identifier
+	}
+	req.Tags = mergeTags(req.Tags, tags)
+	if req.AppID == "" {
+		if _, err := c.requireAppID(); err != nil {
+			return err
+		}
+	}
+
+	createRes, _, err := c.client.Players.Create(req)
+	if err != nil {
+		return err
+	}
+	return c.printOne(createRes, []string{"ID"}, []string{createRes.ID})
+}
+
+func (c *cli) updatePlayer(args []string) error {
+	fs := c.flags("players", "update")
+	file := fs.String("f", "", "JSON or YAML `file` holding the changes, - for the standard input")
+	tags := tagsFlag{}
+	fs.Var(tags, "tag", "`key=value` tag, can be repeated; an empty value deletes the tag")
+	if err := parse(fs, args, 1); err != nil {
+		return err
+	}
+
+	// the device type is sent on every update, so it is read from the
+	// player unless given
+	playerID := fs.Arg(0)
+	player, _, err := c.client.Players.Get(playerID)
+	if err != nil {
+		return err
+	}
+	req := &onesignal.PlayerRequest{AppID: c.appID, DeviceType: player.DeviceType}
+	if err := c.readBody(*file, req); err != nil {
+		return err
+	}
+	req.Tags = mergeTags(req.Tags, tags)
+
+	successRes, _, err := c.client.Players.Update(playerID, req)
+	if err != nil {
+		return err
+	}
+	return c.printOne(successRes, []string{"SUCCESS"}, []string{fmt.Sprint(successRes.Success)})
+}
+
+func (c *cli) exportPlayers(args []string) error {
+	fs := c.flags("players", "export")
+	segment := fs.String("segment", "", "only export the players of the segment `name`")
+	extraFields := fs.String("extra-fields", "", "comma-separated `fields` to add, such as country,external_user_id")
+	lastActiveSince := fs.String("last-active-since", "", "only export the players active since `time`, in RFC 3339 format")
+	if err := parse(fs, args, 0); err != nil {
+		return err
+	}
+	appID, err := c.requireAppID()
+	if err != nil {
+		return err
+	}
+
+	opt := &onesignal.PlayerExportOptions{
+		AppID:       appID,
+		SegmentName: *segment,
+		ExtraFields: splitList(*extraFields),
+	}
+	if *lastActiveSince != "" {
+		if opt.LastActiveSince, err = time.Parse(time.RFC3339, *lastActiveSince); err != nil {
+			return fmt.Errorf("invalid -last-active-since: %v", err)
+		}
+	}
+
+	columns := append(append([]string{}, playerColumns...), opt.ExtraFields...)
+	p := c.newPrinter(columns, true)
+	for player, err := range c.client.Players.ExportAll(context.Background(), opt) {
+		if err != nil {
+			return err
+		}
+		values := playerValues(&player)
+		for _, field := range opt.ExtraFields {
+			values = append(values, player.Extra[field])
+		}
+		if err := p.print(exportedPlayer{player}, values); err != nil {
+			return err
+		}
+	}
+	return p.close()
+}
+
+// exportedPlayer is printed in JSON with the extra fields of the export.
+type exportedPlayer struct {
+	onesignal.Player
+}
+
+func (p exportedPlayer) MarshalJSON() ([]byte, error) {
+	type player onesignal.Player
+	return json.Marshal(struct {
+		player
+		Extra map[string]string `json:"extra,omitempty"`
+	}{player(p.Player), p.Extra})
+}
+
+// mergeTags returns tags with the flags set on top of them.
+func mergeTags(tags map[string]string, flags tagsFlag) map[string]string {
+	if len(flags) == 0 {
+		return tags
+	}
+	if tags == nil {
+		tags = make(map[string]string)
+	}
+	for k, v := range flags {
+		tags[k] = v
+	}
+	return tags
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/csv"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tbalthazar/onesignal-go"
+)
+
+func TestPlayers(t *testing.T) {
+	srv, e := setup(t)
+	appID := e["ONESIGNAL_APP_ID"]
+
+	stdout, stderr, code := e.run("", "-output", "csv", "players", "create", "-device-type", "1", "-identifier", "token1", "-tag", "level=1")
+	if code != 0 {
+		t.Fatalf("players create exited with %d: %s", code, stderr)
+	}
+	playerID := strings.TrimSpace(strings.TrimPrefix(stdout, "ID\n"))
+
+	if _, stderr, code := e.run(`{"language": "fr", "tags": {"premium": "true"}}`, "players", "update", playerID, "-f", "-", "-tag", "level="); code != 0 {
+		t.Fatalf("players update exited with %d: %s", code, stderr)
+	}
+	players := srv.Players(appID)
+	if len(players) != 1 {
+		t.Fatalf("Players returned %+v", players)
+	}
+	if got, want := players[0].Tags, map[string]string{"premium": "true"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("tags are %v, want %v", got, want)
+	}
+	if players[0].DeviceType != onesignal.DeviceTypeAndroid || players[0].Language != "fr" {
+		t.Errorf("player is %+v", players[0])
+	}
+
+	stdout, _, _ = e.run("", "players", "get", playerID)
+	if !strings.Contains(stdout, "premium=true") {
+		t.Errorf("players get printed %q", stdout)
+	}
+
+	srv.AddPlayer(appID, onesignal.Player{ID: "player2"})
+	for _, args := range [][]string{
+		{"-output", "csv", "players", "list"},
+		{"-output", "csv", "players", "list", "-all"},
+		{"-output", "csv", "players", "export", "-extra-fields", "country", "-last-active-since", "1970-01-01T00:00:00Z"},
+	} {
+		stdout, stderr, code := e.run("", args...)
+		if code != 0 {
+			t.Fatalf("%q exited with %d: %s", args, code, stderr)
+		}
+		rows, err := csv.NewReader(strings.NewReader(stdout)).ReadAll()
+		if err != nil {
+			t.Fatalf("%q printed invalid CSV %q: %v", args, stdout, err)
+		}
+		if len(rows) != 3 || rows[1][0] != playerID || rows[2][0] != "player2" {
+			t.Errorf("%q printed %q", args, rows)
+		}
+		if args[2] == "export" && rows[0][len(rows[0])-1] != "country" {
+			t.Errorf("%q printed the columns %q", args, rows[0])
+		}
+	}
+}
+
+func TestPlayers_exportJSON(t *testing.T) {
+	srv, e := setup(t)
+	srv.AddPlayer(e["ONESIGNAL_APP_ID"], onesignal.Player{ID: "player1"})
+
+	stdout, stderr, code := e.run("", "-output", "json", "players", "export", "-extra-fields", "country")
+	if code != 0 {
+		t.Fatalf("players export exited with %d: %s", code, stderr)
+	}
+	if !strings.Contains(stdout, `"id": "player1"`) || !strings.Contains(stdout, `"extra": {`) {
+		t.Errorf("players export printed %q", stdout)
+	}
+}
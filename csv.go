@@ -0,0 +1,145 @@
+package onesignal
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+)
+
+// csvFile reads the rows of a CSV file exported by OneSignal, such as a
+// notification history or a players export.
+type csvFile struct {
+	ctx     context.Context
+	body    io.Closer
+	r       *csv.Reader
+	columns map[string]int
+}
+
+// openCSV downloads the CSV file at rawurl with httpClient and reads its
+// header. The file may be gzipped. The download doesn't go through the
+// middlewares of a Client, since rawurl points outside the OneSignal API and
+// must not receive the API keys.
+func openCSV(ctx context.Context, httpClient *http.Client, rawurl string) (*csvFile, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", rawurl, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, err
+	}
+	if err := CheckResponse(resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+
+	f := &csvFile{ctx: ctx, body: resp.Body}
+	br := bufio.NewReader(resp.Body)
+	var r io.Reader = br
+	if magic, _ := br.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		if r, err = gzip.NewReader(r); err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+	}
+	f.r = csv.NewReader(r)
+	f.r.ReuseRecord = true
+
+	header, err := f.next()
+	if err != nil {
+		resp.Body.Close()
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	f.columns = make(map[string]int, len(header))
+	for i, name := range header {
+		f.columns[name] = i
+	}
+	return f, nil
+}
+
+// defaultPollPolicy polls the CSV files generated by OneSignal: up to 30
+// times, starting after 1s and backing off up to 30s.
+func defaultPollPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 30,
+		BaseDelay:   time.Second,
+		MaxDelay:    30 * time.Second,
+		Jitter:      0.2,
+	}
+}
+
+// pollCSV opens the CSV file at rawurl like openCSV, waiting for OneSignal
+// to generate it: MaxAttempts of poll bounds the number of downloads
+// attempted, which are spaced out with its backoff.
+func pollCSV(ctx context.Context, httpClient *http.Client, rawurl string, poll *RetryPolicy) (*csvFile, error) {
+	for attempt := 1; ; attempt++ {
+		f, err := openCSV(ctx, httpClient, rawurl)
+		// files that don't exist yet are either not found or forbidden,
+		// depending on the permissions of the bucket
+		pending := errors.Is(err, ErrNotFound) || errors.Is(err, ErrUnauthorized)
+		if !pending || attempt >= poll.MaxAttempts {
+			return f, err
+		}
+
+		timer := time.NewTimer(poll.backoff(attempt))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// line returns the line of the last row returned by next.
+func (f *csvFile) line() int {
+	line, _ := f.r.FieldPos(0)
+	return line
+}
+
+// next returns the next row, or io.EOF at the end of the file. The row is
+// only valid until the next call.
+func (f *csvFile) next() ([]string, error) {
+	row, err := f.r.Read()
+	if err != nil && err != io.EOF {
+		if ctxErr := f.ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+	}
+	return row, err
+}
+
+// get returns the value of column in row, or an empty string if the file
+// has no such column.
+func (f *csvFile) get(row []string, column string) string {
+	if i, ok := f.columns[column]; ok && i < len(row) {
+		return row[i]
+	}
+	return ""
+}
+
+// fields returns the values of row by column name.
+func (f *csvFile) fields(row []string) map[string]string {
+	fields := make(map[string]string, len(f.columns))
+	for name, i := range f.columns {
+		if i < len(row) {
+			fields[name] = row[i]
+		}
+	}
+	return fields
+}
+
+func (f *csvFile) Close() error {
+	return f.body.Close()
+}
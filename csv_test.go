@@ -0,0 +1,69 @@
+package onesignal
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestOpenCSV_gzip(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/export.csv.gz", func(w http.ResponseWriter, r *http.Request) {
+		gz := gzip.NewWriter(w)
+		fmt.Fprint(gz, "id,language\nplayerid1,fr\n")
+		gz.Close()
+	})
+
+	f, err := openCSV(context.Background(), client.Client, server.URL+"/export.csv.gz")
+	if err != nil {
+		t.Fatalf("openCSV returned an error: %v", err)
+	}
+	defer f.Close()
+
+	row, err := f.next()
+	if err != nil {
+		t.Fatalf("next returned an error: %v", err)
+	}
+	if got, want := f.get(row, "language"), "fr"; got != want {
+		t.Errorf("language: %v, want %v", got, want)
+	}
+	if got, want := f.get(row, "unknown"), ""; got != want {
+		t.Errorf("unknown: %v, want %v", got, want)
+	}
+	if got, want := f.fields(row), map[string]string{"id": "playerid1", "language": "fr"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("fields: %v, want %v", got, want)
+	}
+
+	if _, err := f.next(); err != io.EOF {
+		t.Errorf("next returned %v, want %v", err, io.EOF)
+	}
+}
+
+func TestOpenCSV_empty(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/export.csv", func(w http.ResponseWriter, r *http.Request) {})
+
+	if _, err := openCSV(context.Background(), client.Client, server.URL+"/export.csv"); err != io.ErrUnexpectedEOF {
+		t.Errorf("openCSV returned %v, want %v", err, io.ErrUnexpectedEOF)
+	}
+}
+
+func TestOpenCSV_canceled(t *testing.T) {
+	setup()
+	defer teardown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := openCSV(ctx, client.Client, server.URL+"/export.csv"); err != context.Canceled {
+		t.Errorf("openCSV returned %v, want %v", err, context.Canceled)
+	}
+}
@@ -1,17 +1,99 @@
 /*
 Package onesignal provides the binding for OneSignal API.
 
-Create a new OneSignal client:
+Create a new OneSignal client. The UserKey is used for the /apps endpoints
+and the AppKey for the other endpoints:
 
-	client := onesignal.NewClient(nil)
+	client, err := onesignal.NewClient(
+		onesignal.WithUserKey("YourOneSignalUserKey"),
+		onesignal.WithAppKey("YourOneSignalAppKey"),
+	)
 
-Set the UserKey if you want to use the /apps endpoints:
+Or read the keys from the ONESIGNAL_APP_KEY, ONESIGNAL_USER_KEY and
+ONESIGNAL_API_URL environment variables:
 
-	client.UserKey = "YourOneSignalUserKey"
+	client, err := onesignal.NewClientFromEnv()
 
-Set the AppKey for the other endpoints:
+Every method has a WithContext variant taking a context.Context as its first
+argument, so that a request can be canceled or given a deadline:
 
-	client.AppKey = "YourOneSignalAppKey"
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	createRes, res, err := client.Notifications.CreateWithContext(ctx, notificationReq)
+
+API errors are returned as an *ErrorResponse keeping the HTTP response, the
+status code, the request ID and the raw body. They can be matched against
+error categories:
+
+	_, _, err := client.Players.Get("unknownPlayerID")
+	if errors.Is(err, onesignal.ErrNotFound) {
+		// ...
+	}
+
+Failed requests can be retried with an exponential backoff. Network errors,
+429 and 5xx responses are retried by default; POST requests such as
+Notifications.Create are only retried if RetryNonIdempotent is set:
+
+	client, err := onesignal.NewClient(
+		onesignal.WithAppKey("YourOneSignalAppKey"),
+		onesignal.WithRetryPolicy(onesignal.DefaultRetryPolicy()),
+	)
+
+A 429 Too Many Requests response is returned as a *RateLimitError holding the
+parsed Retry-After header. To stay under the quota in the first place, set a
+client-side RateLimiter per AuthKeyType:
+
+	limiter, err := onesignal.NewRateLimiter(10, 5) // 10 req/s, bursts of 5
+	client, err := onesignal.NewClient(
+		onesignal.WithAppKey("YourOneSignalAppKey"),
+		onesignal.WithRateLimiter(onesignal.APP, limiter),
+	)
+
+Requests are logged at the debug level to a *slog.Logger, if one is given.
+Bodies are only logged on demand, with the keys and other secrets redacted:
+
+	client, err := onesignal.NewClient(
+		onesignal.WithAppKey("YourOneSignalAppKey"),
+		onesignal.WithLogger(slog.Default()),
+		onesignal.WithBodyLogging(true),
+	)
+
+Cross-cutting behavior such as audit logging, metrics or header injection can
+be added around every API call with middlewares:
+
+	client.Use(func(next onesignal.Doer) onesignal.Doer {
+		return onesignal.DoerFunc(func(r *http.Request) (*http.Response, error) {
+			log.Println(r.Method, r.URL, onesignal.RedactHeader(r.Header))
+			return next.Do(r)
+		})
+	})
+
+OperationFromRequest tells a middleware which logical operation, such as
+"notifications.create", a request belongs to. The otelonesignal package uses
+it to provide OpenTelemetry tracing and metrics.
+
+The onesignaltest package provides an in-memory fake of the OneSignal API, to
+test code using a Client offline:
+
+	srv := onesignaltest.NewServer()
+	defer srv.Close()
+	client, err := srv.Client()
+
+The recorder package records the HTTP interactions of a Client to cassette
+files, with the keys redacted, and replays them in tests:
+
+	rec, err := recorder.New("test-fixtures/cassettes/flow.json", recorder.Replay)
+	defer rec.Stop()
+	client, err := onesignal.NewClient(onesignal.WithHTTPClient(rec.HTTPClient()))
+
+The appconfig package manages apps declaratively from a YAML or JSON file
+holding their desired AppRequest, like the apps plan and apply commands of
+cmd/onesignal:
+
+	cfg, err := appconfig.Load("apps.yaml")
+	plan, err := appconfig.NewPlan(ctx, client, cfg)
+	plan.WriteTo(os.Stdout)
+	apps, err := plan.Apply(ctx, client)
 
 Apps
 
@@ -37,6 +119,20 @@ Update an app:
 	}
 	app, res, err := client.Apps.Update(appID, appRequest)
 
+The apnscert package loads APNs certificates from .p12 files, checked
+locally with their password first, and warns about the uploaded
+certificates close to expiring:
+
+	cert, err := apnscert.SetAPNSCertificateFromFile(appRequest, "push.p12", "password")
+	warnings := apnscert.Check(apps, 30*24*time.Hour)
+
+Generate the Safari web push icons from a square PNG or JPEG image of at
+least 256x256 pixels, host them, and set their URLs:
+
+	err := appRequest.SetSafariIconsFromFile("icon.png", func(size int, png []byte) (string, error) {
+		// store png and return its URL
+	})
+
 Players
 
 List players:
@@ -48,6 +144,15 @@ List players:
 	}
 	listRes, res, err := client.Players.List(listOpt)
 
+Iterate over all the players of an app, fetching pages lazily:
+
+	for player, err := range client.Players.All(ctx, appID, &onesignal.PageOptions{Prefetch: true}) {
+		if err != nil {
+			return err
+		}
+		// ...
+	}
+
 Get player:
 
 	player, res, err := client.Players.Get("playerID")
@@ -129,6 +234,16 @@ Generate a link to download a CSV list of all the players:
 	}
 	CSVEXportRes, res, err := client.Players.CSVExport(opt)
 
+Or export the players and read them once OneSignal has generated the file:
+
+	exportOpt := &onesignal.PlayerExportOptions{
+		AppID:       appID,
+		ExtraFields: []string{"country"},
+	}
+	for player, err := range client.Players.ExportAll(ctx, exportOpt) {
+		// ...
+	}
+
 Update a player:
 
 	player := &onesignal.PlayerRequest{
@@ -147,6 +262,12 @@ List notifications:
 	}
 	listRes, res, err := client.Notifications.List(listOpt)
 
+Iterate over all the notifications of an app:
+
+	for notification, err := range client.Notifications.All(ctx, appID, nil) {
+		// ...
+	}
+
 Get a notification:
 
 	opt := &onesignal.NotificationGetOptions{
@@ -165,6 +286,53 @@ Create a notification:
 	}
 	createRes, res, err := client.Notifications.Create(notificationReq)
 
+Target the users matching filters instead of listing them, with Filters built
+from fields such as Tag, LastSession or Country:
+
+	notificationReq.Filters = onesignal.Tag("level").GreaterThan("10").
+		And(onesignal.LastSession().Within(24 * time.Hour)).
+		Or(onesignal.Country("FR"))
+	createRes, res, err = client.Notifications.Create(notificationReq)
+
+Schedule a notification, here at 9:00AM in the timezone of each user on the
+next day:
+
+	notificationReq.ScheduleAt(time.Now().Add(24 * time.Hour))
+	err = notificationReq.DeliverInUserTimezone(9, 0)
+
+Add action buttons to a notification, for every platform it targets:
+
+	err = notificationReq.SetActions(
+		onesignal.Action{ID: "accept", Text: "Accept", URL: "https://example.com/accept"},
+		onesignal.Action{ID: "decline", Text: "Decline"},
+	)
+
+Create validates the request before sending it, and returns a
+*onesignal.ValidationError listing every invalid field, such as a missing
+app_id or contents. The validation can be turned off with
+onesignal.WithRequestValidation(false), and run on its own with:
+
+	err = notificationReq.Validate()
+
+Create a notification for more players than a single notification can target
+(see MaxPlayerIDsPerNotification), in concurrent batches:
+
+	notificationReq.IncludePlayerIDs = manyPlayerIDs
+	batchRes, err := client.Notifications.CreateBatched(ctx, notificationReq, nil)
+
+Request the list of the players who clicked a notification, and read it once
+OneSignal has generated it:
+
+	historyOpt := &onesignal.NotificationHistoryOptions{
+		AppID:  appID,
+		Events: onesignal.HistoryClicked,
+	}
+	historyRes, res, err := client.Notifications.History(notificationID, historyOpt)
+
+	for record, err := range client.Notifications.HistoryRecords(ctx, historyRes.DestinationURL, nil) {
+		// ...
+	}
+
 Update a notification:
 
 	opt := &onesignal.NotificationUpdateOptions{
@@ -180,5 +348,83 @@ Delete a notification:
 	}
 	successRes, res, err := client.Notifications.Delete(notificationID, opt)
 
+Templates
+
+List templates:
+
+	listOpt := &onesignal.TemplateListOptions{
+		AppID:  appID,
+		Limit:  10,
+		Offset: 0,
+	}
+	listRes, res, err := client.Templates.List(listOpt)
+
+Get a template:
+
+	opt := &onesignal.TemplateGetOptions{
+		AppID: appID,
+	}
+	template, res, err := client.Templates.Get(templateID, opt)
+
+Create a template, and send a notification using it:
+
+	templateReq := &onesignal.TemplateRequest{
+		AppID:    appID,
+		Name:     "Welcome",
+		Contents: map[string]string{"en": "Welcome aboard!"},
+	}
+	template, res, err := client.Templates.Create(templateReq)
+
+	notificationReq.TemplateID = template.ID
+
+Update a template:
+
+	templateReq.Contents = map[string]string{"en": "Welcome!"}
+	template, res, err := client.Templates.Update(templateID, templateReq)
+
+Delete a template:
+
+	opt := &onesignal.TemplateDeleteOptions{
+		AppID: appID,
+	}
+	successRes, res, err := client.Templates.Delete(templateID, opt)
+
+Segments
+
+List the segments of an app:
+
+	listOpt := &onesignal.SegmentListOptions{
+		Limit:  10,
+		Offset: 0,
+	}
+	listRes, res, err := client.Segments.List(appID, listOpt)
+
+Create a segment, with the same Filters as notifications:
+
+	segmentReq := &onesignal.SegmentRequest{
+		Name:    "French players",
+		Filters: onesignal.Country("FR").And(onesignal.SessionCount().GreaterThan("5")),
+	}
+	createRes, res, err := client.Segments.Create(appID, segmentReq)
+
+Delete a segment:
+
+	successRes, res, err := client.Segments.Delete(appID, segmentID)
+
+Outcomes
+
+Count the clicks and sum the session durations of the last day, for the
+outcomes directly attributed to a notification:
+
+	opt := &onesignal.OutcomeListOptions{
+		Outcomes: []onesignal.OutcomeQuery{
+			{Name: onesignal.OutcomeClicks, Aggregation: onesignal.OutcomeCount},
+			{Name: onesignal.OutcomeSessionDuration, Aggregation: onesignal.OutcomeSum},
+		},
+		TimeRange:   onesignal.OutcomeLastDay,
+		Attribution: onesignal.OutcomeAttributionDirect,
+	}
+	listRes, res, err := client.Outcomes.List(appID, opt)
+
 */
 package onesignal
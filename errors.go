@@ -0,0 +1,191 @@
+package onesignal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// Error categories that an API error can be matched against with errors.Is:
+//
+//	if errors.Is(err, onesignal.ErrNotFound) {
+//		// ...
+//	}
+var (
+	// ErrNotFound is matched by 404 Not Found responses.
+	ErrNotFound = errors.New("onesignal: not found")
+
+	// ErrUnauthorized is matched by 401 Unauthorized and 403 Forbidden
+	// responses, usually caused by a wrong AppKey or UserKey.
+	ErrUnauthorized = errors.New("onesignal: unauthorized")
+
+	// ErrInvalidPlayerIDs is matched by errors listing invalid player IDs.
+	ErrInvalidPlayerIDs = errors.New("onesignal: invalid player ids")
+
+	// ErrRateLimited is matched by 429 Too Many Requests responses.
+	ErrRateLimited = errors.New("onesignal: rate limited")
+
+	// ErrServer is matched by 5xx responses.
+	ErrServer = errors.New("onesignal: server error")
+)
+
+// ErrorResponse reports one or more errors caused by an API request.
+type ErrorResponse struct {
+	// Response is the HTTP response that caused this error. Its body has
+	// already been read and is available in Body.
+	Response *http.Response `json:"-"`
+
+	// StatusCode is the HTTP status code of Response.
+	StatusCode int `json:"-"`
+
+	// RequestID is the value of the X-Request-Id response header, if any.
+	RequestID string `json:"-"`
+
+	// Body is the raw response body.
+	Body []byte `json:"-"`
+
+	// Messages are the error messages returned by OneSignal.
+	Messages []string `json:"errors"`
+
+	// InvalidPlayerIDs lists the player IDs OneSignal rejected, if any.
+	InvalidPlayerIDs []string `json:"-"`
+}
+
+func (e *ErrorResponse) Error() string {
+	msg := "OneSignal returned those error messages:\n - "
+	if e.Response != nil && e.Response.Request != nil {
+		msg = fmt.Sprintf("%v %v: %d %s", e.Response.Request.Method, e.Response.Request.URL, e.StatusCode, msg)
+	}
+	if len(e.InvalidPlayerIDs) > 0 {
+		return msg + "invalid player ids: " + strings.Join(e.InvalidPlayerIDs, ", ")
+	}
+	return msg + strings.Join(e.Messages, "\n - ")
+}
+
+// Is reports whether e belongs to the error category target, one of
+// ErrNotFound, ErrUnauthorized, ErrInvalidPlayerIDs, ErrRateLimited or
+// ErrServer.
+func (e *ErrorResponse) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	case ErrInvalidPlayerIDs:
+		return len(e.InvalidPlayerIDs) > 0
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServer:
+		return e.StatusCode >= 500
+	}
+	return false
+}
+
+// ErrorCategory returns a short and stable name for the category of err,
+// suitable as a metric label or span attribute:
+//
+//   - "not_found", "unauthorized", "invalid_player_ids", "rate_limited" and
+//     "server" for the matching error categories,
+//   - "client" for the other API errors,
+//   - "invalid_request" for requests rejected before being sent,
+//   - "canceled" and "timeout" for context errors,
+//   - "network" for transport errors and "other" for anything else.
+//
+// It returns an empty string if err is nil.
+func ErrorCategory(err error) string {
+	var netErr net.Error
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, ErrNotFound):
+		return "not_found"
+	case errors.Is(err, ErrUnauthorized):
+		return "unauthorized"
+	case errors.Is(err, ErrInvalidPlayerIDs):
+		return "invalid_player_ids"
+	case errors.Is(err, ErrRateLimited):
+		return "rate_limited"
+	case errors.Is(err, ErrServer):
+		return "server"
+	case errors.As(err, new(*ErrorResponse)):
+		return "client"
+	case errors.As(err, new(*FieldError)):
+		return "invalid_request"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.As(err, &netErr):
+		return "network"
+	}
+	return "other"
+}
+
+// FieldError reports an invalid field of a request, found before sending it.
+type FieldError struct {
+	// Field is the JSON name of the field, such as "app_id".
+	Field string
+
+	// Message describes what is wrong with the field.
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	return "onesignal: " + e.Field + ": " + e.Message
+}
+
+// ValidationError lists every invalid field of a request. It is returned
+// before the request is sent, so it never comes with an *http.Response.
+type ValidationError struct {
+	Errors []*FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, fieldErr := range e.Errors {
+		msgs[i] = fieldErr.Field + ": " + fieldErr.Message
+	}
+	return "onesignal: invalid request: " + strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the FieldErrors, so that errors.As can find them.
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, fieldErr := range e.Errors {
+		errs[i] = fieldErr
+	}
+	return errs
+}
+
+// decode parses the errors field of a response body, which is either a list
+// of messages or an object such as {"invalid_player_ids": [...]}.
+func (e *ErrorResponse) decode(body []byte) error {
+	var v struct {
+		Errors json.RawMessage `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return err
+	}
+	return e.decodeErrors(v.Errors)
+}
+
+func (e *ErrorResponse) decodeErrors(raw json.RawMessage) error {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+	if raw[0] == '{' {
+		var obj struct {
+			InvalidPlayerIDs []string `json:"invalid_player_ids"`
+		}
+		if err := json.Unmarshal(raw, &obj); err != nil {
+			return err
+		}
+		e.InvalidPlayerIDs = obj.InvalidPlayerIDs
+		return nil
+	}
+	return json.Unmarshal(raw, &e.Messages)
+}
@@ -0,0 +1,170 @@
+package onesignal
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCheckResponse_serverError(t *testing.T) {
+	r := &http.Response{
+		StatusCode: http.StatusInternalServerError,
+		Header:     http.Header{"X-Request-Id": []string{"req-123"}},
+		Body:       ioutil.NopCloser(strings.NewReader("<html>oops</html>")),
+	}
+
+	err, ok := CheckResponse(r).(*ErrorResponse)
+	if !ok {
+		t.Fatalf("CheckResponse return value should be of type ErrorResponse but is %v: %+v", reflect.TypeOf(err), err)
+	}
+
+	if got, want := err.Response, r; got != want {
+		t.Errorf("Response: %v, want %v", got, want)
+	}
+	if got, want := err.StatusCode, http.StatusInternalServerError; got != want {
+		t.Errorf("StatusCode: %v, want %v", got, want)
+	}
+	if got, want := err.RequestID, "req-123"; got != want {
+		t.Errorf("RequestID: %v, want %v", got, want)
+	}
+	if got, want := string(err.Body), "<html>oops</html>"; got != want {
+		t.Errorf("Body: %v, want %v", got, want)
+	}
+	if !errors.Is(err, ErrServer) {
+		t.Errorf("errors.Is(%v, ErrServer) should be true", err)
+	}
+}
+
+func TestCheckResponse_invalidPlayerIDs(t *testing.T) {
+	r := &http.Response{
+		StatusCode: http.StatusBadRequest,
+		Body: ioutil.NopCloser(strings.NewReader(`{
+			"errors": {
+				"invalid_player_ids": ["5fdc92b2-3b2a-11e5-ac13-8fdccfe4d986"]
+			}
+		}`)),
+	}
+
+	err := CheckResponse(r)
+
+	var errResp *ErrorResponse
+	if !errors.As(err, &errResp) {
+		t.Fatalf("CheckResponse return value should be of type ErrorResponse but is %v: %+v", reflect.TypeOf(err), err)
+	}
+	want := []string{"5fdc92b2-3b2a-11e5-ac13-8fdccfe4d986"}
+	if got := errResp.InvalidPlayerIDs; !reflect.DeepEqual(got, want) {
+		t.Errorf("InvalidPlayerIDs: %v, want %v", got, want)
+	}
+	if !errors.Is(err, ErrInvalidPlayerIDs) {
+		t.Errorf("errors.Is(%v, ErrInvalidPlayerIDs) should be true", err)
+	}
+}
+
+func TestErrorResponse_Is(t *testing.T) {
+	categories := []error{ErrNotFound, ErrUnauthorized, ErrInvalidPlayerIDs, ErrRateLimited, ErrServer}
+
+	tests := []struct {
+		err  error
+		want error
+	}{
+		{&ErrorResponse{StatusCode: http.StatusNotFound}, ErrNotFound},
+		{&ErrorResponse{StatusCode: http.StatusUnauthorized}, ErrUnauthorized},
+		{&ErrorResponse{StatusCode: http.StatusForbidden}, ErrUnauthorized},
+		{&ErrorResponse{StatusCode: http.StatusBadRequest, InvalidPlayerIDs: []string{"id"}}, ErrInvalidPlayerIDs},
+		{&ErrorResponse{StatusCode: http.StatusTooManyRequests}, ErrRateLimited},
+		{&RateLimitError{ErrorResponse: &ErrorResponse{StatusCode: http.StatusTooManyRequests}}, ErrRateLimited},
+		{&ErrorResponse{StatusCode: http.StatusBadGateway}, ErrServer},
+		{&ErrorResponse{StatusCode: http.StatusBadRequest}, nil},
+	}
+	for _, tt := range tests {
+		for _, category := range categories {
+			if got, want := errors.Is(tt.err, category), category == tt.want; got != want {
+				t.Errorf("errors.Is(%v, %v) = %v, want %v", tt.err, category, got, want)
+			}
+		}
+	}
+}
+
+func TestErrorCategory(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, ""},
+		{&ErrorResponse{StatusCode: http.StatusNotFound}, "not_found"},
+		{&ErrorResponse{StatusCode: http.StatusUnauthorized}, "unauthorized"},
+		{&ErrorResponse{StatusCode: http.StatusBadRequest, InvalidPlayerIDs: []string{"id"}}, "invalid_player_ids"},
+		{&RateLimitError{ErrorResponse: &ErrorResponse{StatusCode: http.StatusTooManyRequests}}, "rate_limited"},
+		{&ErrorResponse{StatusCode: http.StatusInternalServerError}, "server"},
+		{&ErrorResponse{StatusCode: http.StatusBadRequest}, "client"},
+		{context.Canceled, "canceled"},
+		{context.DeadlineExceeded, "timeout"},
+		{&url.Error{Op: "Get", URL: "/", Err: errors.New("connection refused")}, "network"},
+		{&ValidationError{Errors: []*FieldError{{Field: "app_id", Message: "required"}}}, "invalid_request"},
+		{errors.New("unexpected EOF"), "other"},
+	}
+	for _, tt := range tests {
+		if got := ErrorCategory(tt.err); got != tt.want {
+			t.Errorf("ErrorCategory(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestErrorResponse_asFromRateLimitError(t *testing.T) {
+	var err error = &RateLimitError{
+		ErrorResponse: &ErrorResponse{StatusCode: http.StatusTooManyRequests},
+	}
+
+	var errResp *ErrorResponse
+	if !errors.As(err, &errResp) {
+		t.Fatalf("errors.As should find the ErrorResponse in %v", err)
+	}
+	if got, want := errResp.StatusCode, http.StatusTooManyRequests; got != want {
+		t.Errorf("StatusCode: %v, want %v", got, want)
+	}
+}
+
+func TestErrorResponse_Error(t *testing.T) {
+	u, _ := url.Parse("https://onesignal.com/api/v1/players/123")
+	err := &ErrorResponse{
+		Response: &http.Response{
+			Request: &http.Request{Method: "GET", URL: u},
+		},
+		StatusCode: http.StatusNotFound,
+		Messages:   []string{"No user with this id found"},
+	}
+
+	want := "GET https://onesignal.com/api/v1/players/123: 404 OneSignal returned those error messages:\n - No user with this id found"
+	if got := err.Error(); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestDo_errorKeepsResponse(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/players/unknown", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"errors": ["No user with this id found"]}`))
+	})
+
+	_, resp, err := client.Players.Get("unknown")
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get returned %v, want an ErrNotFound error", err)
+	}
+
+	var errResp *ErrorResponse
+	errors.As(err, &errResp)
+	if got, want := errResp.Response, resp; got != want {
+		t.Errorf("Response: %v, want %v", got, want)
+	}
+	if got, want := errResp.Response.Request.Method, "GET"; got != want {
+		t.Errorf("Request method: %v, want %v", got, want)
+	}
+}
@@ -20,7 +20,7 @@ func ListApps(client *onesignal.Client) {
 
 	apps, res, err := client.Apps.List()
 	if err != nil {
-		fmt.Printf("--- res:%+v, err:%+v\n", res)
+		fmt.Printf("--- res:%+v, err:%+v\n", res, err)
 		log.Fatal(err)
 	}
 	fmt.Printf("--- apps:%+v\n", apps)
@@ -32,7 +32,7 @@ func GetApps(appID string, client *onesignal.Client) {
 
 	app, res, err := client.Apps.Get(appID)
 	if err != nil {
-		fmt.Printf("--- res:%+v, err:%+v\n", res)
+		fmt.Printf("--- res:%+v, err:%+v\n", res, err)
 		log.Fatal(err)
 	}
 	fmt.Printf("--- app:%+v\n", app)
@@ -46,7 +46,7 @@ func CreateApps(client *onesignal.Client) string {
 	}
 	createRes, res, err := client.Apps.Create(app)
 	if err != nil {
-		fmt.Printf("--- res:%+v, err:%+v\n", res)
+		fmt.Printf("--- res:%+v, err:%+v\n", res, err)
 		log.Fatal(err)
 	}
 	fmt.Printf("--- createRes:%+v\n", createRes)
@@ -63,7 +63,7 @@ func UpdateApps(appID string, client *onesignal.Client) {
 	}
 	updateRes, res, err := client.Apps.Update(appID, app)
 	if err != nil {
-		fmt.Printf("--- res:%+v, err:%+v\n", res)
+		fmt.Printf("--- res:%+v, err:%+v\n", res, err)
 		log.Fatal(err)
 	}
 	fmt.Printf("--- updateRes:%+v\n", updateRes)
@@ -80,7 +80,7 @@ func ListPlayers(client *onesignal.Client) {
 
 	listRes, res, err := client.Players.List(listOpt)
 	if err != nil {
-		fmt.Printf("--- res:%+v, err:%+v\n", res)
+		fmt.Printf("--- res:%+v, err:%+v\n", res, err)
 		log.Fatal(err)
 	}
 	fmt.Printf("--- listRes:%+v\n", listRes)
@@ -93,7 +93,7 @@ func GetPlayer(playerID string, client *onesignal.Client) {
 
 	player, res, err := client.Players.Get(playerID)
 	if err != nil {
-		fmt.Printf("--- res:%+v, err:%+v\n", res)
+		fmt.Printf("--- res:%+v, err:%+v\n", res, err)
 		log.Fatal(err)
 	}
 	fmt.Printf("--- player:%+v\n", player)
@@ -127,7 +127,7 @@ func CreatePlayer(client *onesignal.Client) (playerID string) {
 	}
 	createRes, res, err := client.Players.Create(player)
 	if err != nil {
-		fmt.Printf("--- res:%+v, err:%+v\n", res)
+		fmt.Printf("--- res:%+v, err:%+v\n", res, err)
 		log.Fatal(err)
 	}
 	fmt.Printf("--- createRes:%+v\n", createRes)
@@ -155,7 +155,7 @@ func OnSessionPlayer(playerID string, client *onesignal.Client) {
 
 	onSessionRes, res, err := client.Players.OnSession(playerID, opt)
 	if err != nil {
-		fmt.Printf("--- res:%+v, err:%+v\n", res)
+		fmt.Printf("--- res:%+v, err:%+v\n", res, err)
 		log.Fatal(err)
 	}
 	fmt.Printf("--- onSessionRes:%+v\n", onSessionRes)
@@ -181,7 +181,7 @@ func OnPurchasePlayer(playerID string, client *onesignal.Client) {
 
 	onPurchaseRes, res, err := client.Players.OnPurchase(playerID, opt)
 	if err != nil {
-		fmt.Printf("--- res:%+v, err:%+v\n", res)
+		fmt.Printf("--- res:%+v, err:%+v\n", res, err)
 		log.Fatal(err)
 	}
 	fmt.Printf("--- onPurchaseRes:%+v\n", onPurchaseRes)
@@ -197,7 +197,7 @@ func OnFocusPlayer(playerID string, client *onesignal.Client) {
 
 	onFocusRes, res, err := client.Players.OnFocus(playerID, opt)
 	if err != nil {
-		fmt.Printf("--- res:%+v, err:%+v\n", res)
+		fmt.Printf("--- res:%+v, err:%+v\n", res, err)
 		log.Fatal(err)
 	}
 	fmt.Printf("--- onFocusRes:%+v\n", onFocusRes)
@@ -212,7 +212,7 @@ func CSVExportPlayer(client *onesignal.Client) {
 
 	CSVEXportRes, res, err := client.Players.CSVExport(opt)
 	if err != nil {
-		fmt.Printf("--- res:%+v, err:%+v\n", res)
+		fmt.Printf("--- res:%+v, err:%+v\n", res, err)
 		log.Fatal(err)
 	}
 	fmt.Printf("--- CSVEXportRes:%+v\n", CSVEXportRes)
@@ -227,7 +227,7 @@ func UpdatePlayer(playerID string, client *onesignal.Client) {
 
 	updateRes, res, err := client.Players.Update(playerID, player)
 	if err != nil {
-		fmt.Printf("--- res:%+v, err:%+v\n", res)
+		fmt.Printf("--- res:%+v, err:%+v\n", res, err)
 		log.Fatal(err)
 	}
 	fmt.Printf("--- updateRes:%+v\n", updateRes)
@@ -244,7 +244,7 @@ func ListNotifications(client *onesignal.Client) []onesignal.Notification {
 
 	listRes, res, err := client.Notifications.List(listOpt)
 	if err != nil {
-		fmt.Printf("--- res:%+v, err:%+v\n", res)
+		fmt.Printf("--- res:%+v, err:%+v\n", res, err)
 		log.Fatal(err)
 	}
 	fmt.Printf("--- listRes:%+v\n", listRes)
@@ -261,7 +261,7 @@ func GetNotifications(notificationID string, client *onesignal.Client) *onesigna
 
 	getRes, res, err := client.Notifications.Get(notificationID, opt)
 	if err != nil {
-		fmt.Printf("--- res:%+v, err:%+v\n", res)
+		fmt.Printf("--- res:%+v, err:%+v\n", res, err)
 		log.Fatal(err)
 	}
 	fmt.Printf("--- getRes:%+v\n", getRes)
@@ -282,7 +282,7 @@ func CreateNotifications(client *onesignal.Client) string {
 
 	createRes, res, err := client.Notifications.Create(notificationReq)
 	if err != nil {
-		fmt.Printf("--- res:%+v, err:%+v\n", res)
+		fmt.Printf("--- res:%+v, err:%+v\n", res, err)
 		log.Fatal(err)
 	}
 	fmt.Printf("--- createRes:%+v\n", createRes)
@@ -300,7 +300,7 @@ func UpdateNotifications(notificationID string, client *onesignal.Client) {
 
 	updateRes, res, err := client.Notifications.Update(notificationID, opt)
 	if err != nil {
-		fmt.Printf("--- res:%+v, err:%+v\n", res)
+		fmt.Printf("--- res:%+v, err:%+v\n", res, err)
 		log.Fatal(err)
 	}
 	fmt.Printf("--- updateRes:%+v\n", updateRes)
@@ -315,7 +315,7 @@ func DeleteNotifications(notificationID string, client *onesignal.Client) {
 
 	deleteRes, res, err := client.Notifications.Delete(notificationID, opt)
 	if err != nil {
-		fmt.Printf("--- res:%+v, err:%+v\n", res)
+		fmt.Printf("--- res:%+v, err:%+v\n", res, err)
 		log.Fatal(err)
 	}
 	fmt.Printf("--- deleteRes:%+v\n", deleteRes)
@@ -330,9 +330,13 @@ func main() {
 	appID = os.Getenv("ONESIGNAL_APP_ID")
 	appKey = os.Getenv("ONESIGNAL_API_KEY")
 	userKey = os.Getenv("ONESIGNAL_USER_AUTH_KEY")
-	client := onesignal.NewClient(nil)
-	client.AppKey = appKey
-	client.UserKey = userKey
+	client, err := onesignal.NewClient(
+		onesignal.WithAppKey(appKey),
+		onesignal.WithUserKey(userKey),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	// apps
 	// ListApps(client)
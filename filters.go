@@ -0,0 +1,353 @@
+package onesignal
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Filters targets users by their properties, for NotificationRequest.Filters.
+// Filters are built from a field, such as Tag or LastSession, and a relation,
+// then combined with And and Or:
+//
+//	filters := onesignal.Tag("level").GreaterThan("10").
+//		And(onesignal.LastSession().Within(24 * time.Hour)).
+//		Or(onesignal.Country("FR"))
+//
+// And and Or combine the filters they are called on as a whole, in the order
+// of the calls: the example above targets the users whose level is above 10
+// and who were active in the last 24 hours, along with all the users in
+// France, while a.Or(b).And(c) targets the users matched by a or b, and c.
+//
+// The OneSignal API reads a flat list of conditions where And binds tighter
+// than Or, so And is distributed over the Or of its operands:
+// a.Or(b).And(c) is sent as a and c, or b and c.
+//
+// A relation that doesn't apply to a field, or an invalid value, makes the
+// Filters invalid: Err reports the problem, and encoding the Filters to JSON
+// fails so that no request is sent.
+type Filters struct {
+	conditions []map[string]string
+	err        error
+}
+
+// Err returns the first error found while building f, or nil.
+func (f Filters) Err() error {
+	return f.err
+}
+
+// And returns the filters matching the users matched by both f and g.
+func (f Filters) And(g Filters) Filters {
+	if len(f.conditions) == 0 || len(g.conditions) == 0 {
+		return f.join(g, nil)
+	}
+
+	// (f1 or f2) and (g1 or g2) is sent as
+	// f1 and g1, or f1 and g2, or f2 and g1, or f2 and g2
+	var and Filters
+	for _, fGroup := range f.groups() {
+		for _, gGroup := range g.groups() {
+			and = and.Or(Filters{conditions: append(append([]map[string]string{}, fGroup...), gGroup...)})
+		}
+	}
+	and.err = f.firstErr(g)
+	return and
+}
+
+// Or returns the filters matching the users matched by f or g.
+func (f Filters) Or(g Filters) Filters {
+	return f.join(g, map[string]string{"operator": "OR"})
+}
+
+// groups splits f into the groups of conditions separated by OR operators.
+func (f Filters) groups() [][]map[string]string {
+	var groups [][]map[string]string
+	start := 0
+	for i, condition := range f.conditions {
+		if condition["operator"] == "OR" {
+			groups = append(groups, f.conditions[start:i])
+			start = i + 1
+		}
+	}
+	return append(groups, f.conditions[start:])
+}
+
+// firstErr returns the error of f, or else of g.
+func (f Filters) firstErr(g Filters) error {
+	if f.err != nil {
+		return f.err
+	}
+	return g.err
+}
+
+func (f Filters) join(g Filters, operator map[string]string) Filters {
+	err := f.firstErr(g)
+
+	conditions := make([]map[string]string, 0, len(f.conditions)+len(g.conditions)+1)
+	conditions = append(conditions, f.conditions...)
+	if operator != nil && len(f.conditions) > 0 && len(g.conditions) > 0 {
+		conditions = append(conditions, operator)
+	}
+	conditions = append(conditions, g.conditions...)
+
+	return Filters{conditions: conditions, err: err}
+}
+
+// MarshalJSON encodes f to the wire format of the filters parameter, or
+// returns f.Err().
+func (f Filters) MarshalJSON() ([]byte, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	if f.conditions == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(f.conditions)
+}
+
+// UnmarshalJSON decodes filters in the wire format.
+func (f *Filters) UnmarshalJSON(b []byte) error {
+	var conditions []map[string]string
+	if err := json.Unmarshal(b, &conditions); err != nil {
+		return err
+	}
+	*f = Filters{conditions: conditions}
+	return nil
+}
+
+// valueKind is the type of the values a filter field is compared to.
+type valueKind int
+
+const (
+	stringValue valueKind = iota
+	intValue
+	floatValue
+	hoursAgoValue
+)
+
+type fieldSpec struct {
+	kind      valueKind
+	relations []string
+}
+
+var fieldSpecs = map[string]fieldSpec{
+	"tag":           {stringValue, []string{">", "<", "=", "!=", "exists", "not_exists", "time_elapsed_gt", "time_elapsed_lt"}},
+	"last_session":  {hoursAgoValue, []string{">", "<"}},
+	"first_session": {hoursAgoValue, []string{">", "<"}},
+	"session_count": {intValue, []string{">", "<", "=", "!="}},
+	"session_time":  {floatValue, []string{">", "<"}},
+	"amount_spent":  {floatValue, []string{">", "<", "="}},
+	"bought_sku":    {floatValue, []string{">", "<", "="}},
+	"language":      {stringValue, []string{"=", "!="}},
+	"app_version":   {stringValue, []string{">", "<", "=", "!="}},
+}
+
+// A FilterField is a user property that Filters can compare to a value. The
+// relations that don't apply to the field make the resulting Filters
+// invalid.
+type FilterField struct {
+	field string
+	key   string
+}
+
+// Tag returns the field of the tag with the given key. Tags support every
+// relation except Within and OlderThan.
+func Tag(key string) FilterField {
+	return FilterField{field: "tag", key: key}
+}
+
+// LastSession returns the field of the time of the users' last session. It
+// supports Within and OlderThan.
+func LastSession() FilterField {
+	return FilterField{field: "last_session"}
+}
+
+// FirstSession returns the field of the time of the users' first session.
+// It supports Within and OlderThan.
+func FirstSession() FilterField {
+	return FilterField{field: "first_session"}
+}
+
+// SessionCount returns the field of the number of sessions of the users. It
+// supports GreaterThan, LessThan, Equals and NotEquals with integers.
+func SessionCount() FilterField {
+	return FilterField{field: "session_count"}
+}
+
+// SessionTime returns the field of the total time spent in the app by the
+// users, in seconds. It supports GreaterThan and LessThan with numbers.
+func SessionTime() FilterField {
+	return FilterField{field: "session_time"}
+}
+
+// AmountSpent returns the field of the total amount of money spent by the
+// users. It supports GreaterThan, LessThan and Equals with numbers.
+func AmountSpent() FilterField {
+	return FilterField{field: "amount_spent"}
+}
+
+// BoughtSKU returns the field of the amount spent by the users on the given
+// SKU. It supports GreaterThan, LessThan and Equals with numbers.
+func BoughtSKU(sku string) FilterField {
+	return FilterField{field: "bought_sku", key: sku}
+}
+
+// Language returns the field of the language code of the users. It
+// supports Equals and NotEquals.
+func Language() FilterField {
+	return FilterField{field: "language"}
+}
+
+// AppVersion returns the field of the app version of the users. It supports
+// GreaterThan, LessThan, Equals and NotEquals.
+func AppVersion() FilterField {
+	return FilterField{field: "app_version"}
+}
+
+// Country returns the filters matching the users in the country with the
+// given ISO 3166-1 alpha-2 code.
+func Country(code string) Filters {
+	return Filters{conditions: []map[string]string{{
+		"field":    "country",
+		"relation": "=",
+		"value":    code,
+	}}}
+}
+
+// Location returns the filters matching the users within radius meters of
+// the given coordinates.
+func Location(radius, lat, long float64) Filters {
+	return Filters{conditions: []map[string]string{{
+		"field":  "location",
+		"radius": formatFloat(radius),
+		"lat":    formatFloat(lat),
+		"long":   formatFloat(long),
+	}}}
+}
+
+// Email returns the filters matching the user with the given email address.
+func Email(email string) Filters {
+	return Filters{conditions: []map[string]string{{
+		"field": "email",
+		"value": email,
+	}}}
+}
+
+// GreaterThan returns the filters matching the users whose field is greater
+// than value.
+func (f FilterField) GreaterThan(value string) Filters {
+	return f.compare(">", value)
+}
+
+// LessThan returns the filters matching the users whose field is less than
+// value.
+func (f FilterField) LessThan(value string) Filters {
+	return f.compare("<", value)
+}
+
+// Equals returns the filters matching the users whose field equals value.
+func (f FilterField) Equals(value string) Filters {
+	return f.compare("=", value)
+}
+
+// NotEquals returns the filters matching the users whose field doesn't
+// equal value.
+func (f FilterField) NotEquals(value string) Filters {
+	return f.compare("!=", value)
+}
+
+// Exists returns the filters matching the users who have the tag.
+func (f FilterField) Exists() Filters {
+	return f.build("exists", "value", "")
+}
+
+// NotExists returns the filters matching the users who don't have the tag.
+func (f FilterField) NotExists() Filters {
+	return f.build("not_exists", "value", "")
+}
+
+// TimeElapsedGreaterThan returns the filters matching the users whose tag,
+// a Unix timestamp, is older than d.
+func (f FilterField) TimeElapsedGreaterThan(d time.Duration) Filters {
+	return f.build("time_elapsed_gt", "value", formatFloat(d.Seconds()))
+}
+
+// TimeElapsedLessThan returns the filters matching the users whose tag, a
+// Unix timestamp, is more recent than d.
+func (f FilterField) TimeElapsedLessThan(d time.Duration) Filters {
+	return f.build("time_elapsed_lt", "value", formatFloat(d.Seconds()))
+}
+
+// Within returns the filters matching the users whose session happened
+// less than d ago.
+func (f FilterField) Within(d time.Duration) Filters {
+	return f.build("<", "hours_ago", formatFloat(d.Hours()))
+}
+
+// OlderThan returns the filters matching the users whose session happened
+// more than d ago.
+func (f FilterField) OlderThan(d time.Duration) Filters {
+	return f.build(">", "hours_ago", formatFloat(d.Hours()))
+}
+
+// compare builds a condition comparing the field to value.
+func (f FilterField) compare(relation, value string) Filters {
+	spec := fieldSpecs[f.field]
+	var err error
+	switch spec.kind {
+	case intValue:
+		_, err = strconv.Atoi(value)
+	case floatValue:
+		_, err = strconv.ParseFloat(value, 64)
+	case hoursAgoValue:
+		return f.invalid(relation, "use Within or OlderThan")
+	}
+	if err != nil {
+		return f.invalid(relation, fmt.Sprintf("%q is not a number", value))
+	}
+	return f.build(relation, "value", value)
+}
+
+// build checks that relation applies to the field and builds the condition.
+func (f FilterField) build(relation, valueKey, value string) Filters {
+	spec := fieldSpecs[f.field]
+	if (valueKey == "hours_ago") != (spec.kind == hoursAgoValue) || !contains(spec.relations, relation) {
+		return f.invalid(relation, "unsupported relation")
+	}
+
+	condition := map[string]string{
+		"field":    f.field,
+		"relation": relation,
+	}
+	if f.key != "" {
+		condition["key"] = f.key
+	}
+	// only the existence relations have no value: an empty value is
+	// compared like any other
+	if relation != "exists" && relation != "not_exists" {
+		condition[valueKey] = value
+	}
+	return Filters{conditions: []map[string]string{condition}}
+}
+
+func (f FilterField) invalid(relation, reason string) Filters {
+	name := f.field
+	if f.key != "" {
+		name += " " + strconv.Quote(f.key)
+	}
+	return Filters{err: fmt.Errorf("onesignal: invalid filter on %s with relation %q: %s", name, relation, reason)}
+}
+
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
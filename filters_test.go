@@ -0,0 +1,274 @@
+package onesignal
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFilters_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		filters Filters
+		want    string
+	}{
+		{
+			name:    "tag",
+			filters: Tag("level").GreaterThan("10"),
+			want:    `[{"field":"tag","key":"level","relation":">","value":"10"}]`,
+		},
+		{
+			name:    "tag exists",
+			filters: Tag("vip").Exists(),
+			want:    `[{"field":"tag","key":"vip","relation":"exists"}]`,
+		},
+		{
+			name:    "tag time elapsed",
+			filters: Tag("last_purchase").TimeElapsedGreaterThan(time.Hour),
+			want:    `[{"field":"tag","key":"last_purchase","relation":"time_elapsed_gt","value":"3600"}]`,
+		},
+		{
+			name:    "last session",
+			filters: LastSession().Within(36 * time.Hour),
+			want:    `[{"field":"last_session","hours_ago":"36","relation":"<"}]`,
+		},
+		{
+			name:    "first session",
+			filters: FirstSession().OlderThan(90 * time.Minute),
+			want:    `[{"field":"first_session","hours_ago":"1.5","relation":">"}]`,
+		},
+		{
+			name:    "session count",
+			filters: SessionCount().NotEquals("3"),
+			want:    `[{"field":"session_count","relation":"!=","value":"3"}]`,
+		},
+		{
+			name:    "amount spent",
+			filters: AmountSpent().GreaterThan("9.99"),
+			want:    `[{"field":"amount_spent","relation":">","value":"9.99"}]`,
+		},
+		{
+			name:    "bought sku",
+			filters: BoughtSKU("com.example.gems").Equals("1.99"),
+			want:    `[{"field":"bought_sku","key":"com.example.gems","relation":"=","value":"1.99"}]`,
+		},
+		{
+			name:    "language",
+			filters: Language().Equals("fr"),
+			want:    `[{"field":"language","relation":"=","value":"fr"}]`,
+		},
+		{
+			name:    "app version",
+			filters: AppVersion().LessThan("1.2"),
+			want:    `[{"field":"app_version","relation":"<","value":"1.2"}]`,
+		},
+		{
+			name:    "country",
+			filters: Country("FR"),
+			want:    `[{"field":"country","relation":"=","value":"FR"}]`,
+		},
+		{
+			name:    "location",
+			filters: Location(1000, 50.85, 4.35),
+			want:    `[{"field":"location","lat":"50.85","long":"4.35","radius":"1000"}]`,
+		},
+		{
+			name:    "email",
+			filters: Email("jane@example.com"),
+			want:    `[{"field":"email","value":"jane@example.com"}]`,
+		},
+		{
+			name:    "empty",
+			filters: Filters{},
+			want:    `[]`,
+		},
+	}
+
+	for _, tt := range tests {
+		b, err := json.Marshal(tt.filters)
+		if err != nil {
+			t.Errorf("%s: Marshal returned an error: %v", tt.name, err)
+			continue
+		}
+		var got, want interface{}
+		json.Unmarshal(b, &got)
+		json.Unmarshal([]byte(tt.want), &want)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: Marshal = %s, want %s", tt.name, b, tt.want)
+		}
+	}
+}
+
+func TestFilters_AndOr(t *testing.T) {
+	filters := Tag("level").GreaterThan("10").
+		And(LastSession().Within(24 * time.Hour)).
+		Or(Country("FR"))
+
+	b, err := json.Marshal(filters)
+	if err != nil {
+		t.Fatalf("Marshal returned an error: %v", err)
+	}
+
+	got := []map[string]string{}
+	json.Unmarshal(b, &got)
+	want := []map[string]string{
+		{"field": "tag", "key": "level", "relation": ">", "value": "10"},
+		{"field": "last_session", "relation": "<", "hours_ago": "24"},
+		{"operator": "OR"},
+		{"field": "country", "relation": "=", "value": "FR"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Filters: %+v, want %+v", got, want)
+	}
+}
+
+func TestFilters_AndDistributesOverOr(t *testing.T) {
+	a := Tag("a").Equals("1")
+	b := Tag("b").Equals("2")
+	c := Tag("c").Equals("3")
+	fr := Country("FR")
+	tests := []struct {
+		name    string
+		filters Filters
+		want    []Filters
+	}{
+		{"(a or b) and FR", a.Or(b).And(fr), []Filters{a, fr, {}, b, fr}},
+		{"FR and (a or b)", fr.And(a.Or(b)), []Filters{fr, a, {}, fr, b}},
+		{"(a or b) and (c or FR)", a.Or(b).And(c.Or(fr)), []Filters{a, c, {}, a, fr, {}, b, c, {}, b, fr}},
+		{"a and b or c", a.And(b).Or(c), []Filters{a, b, {}, c}},
+	}
+	for _, tt := range tests {
+		// {} stands for an OR operator
+		var want []map[string]string
+		for _, f := range tt.want {
+			if f.conditions == nil {
+				want = append(want, map[string]string{"operator": "OR"})
+			}
+			want = append(want, f.conditions...)
+		}
+
+		b, err := json.Marshal(tt.filters)
+		if err != nil {
+			t.Fatalf("%s: Marshal returned an error: %v", tt.name, err)
+		}
+		got := []map[string]string{}
+		json.Unmarshal(b, &got)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: Filters: %+v, want %+v", tt.name, got, want)
+		}
+	}
+}
+
+func TestFilters_AndKeepsErr(t *testing.T) {
+	filters := Tag("a").Equals("1").Or(Tag("b").Equals("2")).And(SessionCount().Equals("many"))
+	if filters.Err() == nil {
+		t.Errorf("Err returned nil, want the error of the invalid session count")
+	}
+}
+
+func TestFilters_emptyValue(t *testing.T) {
+	b, _ := json.Marshal(Tag("k").Equals(""))
+	if got, want := string(b), `[{"field":"tag","key":"k","relation":"=","value":""}]`; got != want {
+		t.Errorf("Marshal = %s, want %s", got, want)
+	}
+}
+
+func TestFilters_OrWithEmpty(t *testing.T) {
+	filters := Filters{}.Or(Country("FR")).Or(Filters{})
+
+	b, _ := json.Marshal(filters)
+	if got, want := string(b), `[{"field":"country","relation":"=","value":"FR"}]`; got != want {
+		t.Errorf("Marshal = %s, want %s", got, want)
+	}
+}
+
+func TestFilters_invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		filters Filters
+	}{
+		{"relation not supported by field", Language().GreaterThan("fr")},
+		{"exists on non tag", SessionCount().Exists()},
+		{"value on session", LastSession().GreaterThan("24")},
+		{"hours ago on non session", Tag("level").Within(time.Hour)},
+		{"non integer session count", SessionCount().Equals("many")},
+		{"non numeric amount", AmountSpent().LessThan("ten")},
+		{"invalid in And", Country("FR").And(SessionTime().Equals("10"))},
+		{"invalid in Or", AmountSpent().Equals("x").Or(Country("FR"))},
+	}
+
+	for _, tt := range tests {
+		if tt.filters.Err() == nil {
+			t.Errorf("%s: Err should not be nil", tt.name)
+		}
+		if _, err := json.Marshal(tt.filters); err == nil {
+			t.Errorf("%s: Marshal should return an error", tt.name)
+		}
+	}
+}
+
+func TestFilters_UnmarshalJSON(t *testing.T) {
+	in := `[{"field":"tag","key":"level","relation":">","value":"10"},{"operator":"OR"},{"field":"country","relation":"=","value":"FR"}]`
+
+	var filters Filters
+	if err := json.Unmarshal([]byte(in), &filters); err != nil {
+		t.Fatalf("Unmarshal returned an error: %v", err)
+	}
+
+	want := Tag("level").GreaterThan("10").Or(Country("FR"))
+	if !reflect.DeepEqual(filters, want) {
+		t.Errorf("Filters: %+v, want %+v", filters, want)
+	}
+}
+
+func TestNotificationsService_Create_filters(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/notifications", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Filters []map[string]string `json:"filters"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		want := []map[string]string{
+			{"field": "tag", "key": "level", "relation": "=", "value": "10"},
+		}
+		if !reflect.DeepEqual(body.Filters, want) {
+			t.Errorf("Request filters: %+v, want %+v", body.Filters, want)
+		}
+		w.Write([]byte(`{"id": "notif-fake-id", "recipients": 1}`))
+	})
+
+	_, _, err := client.Notifications.Create(&NotificationRequest{
+		AppID:    "id123",
+		Contents: map[string]string{"en": "English message"},
+		Filters:  Tag("level").Equals("10"),
+	})
+	if err != nil {
+		t.Errorf("Create returned an error: %v", err)
+	}
+}
+
+func TestNotificationsService_Create_invalidFilters(t *testing.T) {
+	setup()
+	defer teardown()
+
+	requestSent := false
+	mux.HandleFunc("/notifications", func(w http.ResponseWriter, r *http.Request) {
+		requestSent = true
+	})
+
+	_, _, err := client.Notifications.Create(&NotificationRequest{
+		AppID:   "id123",
+		Filters: Language().GreaterThan("fr"),
+	})
+	if err == nil || !strings.Contains(err.Error(), "language") {
+		t.Errorf("Create returned %v, want an invalid filter error", err)
+	}
+	if requestSent {
+		t.Errorf("Request should not have been sent")
+	}
+}
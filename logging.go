@@ -0,0 +1,133 @@
+package onesignal
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const redacted = "REDACTED"
+
+// sensitiveFields are the JSON fields whose values are never logged.
+var sensitiveFields = map[string]bool{
+	"apns_p12":                 true,
+	"apns_p12_password":        true,
+	"safari_apns_p12":          true,
+	"safari_apns_p12_password": true,
+	"basic_auth_key":           true,
+	"gcm_key":                  true,
+	"chrome_key":               true,
+	"chrome_web_key":           true,
+}
+
+// IsSensitiveField tells whether the JSON field name, such as "gcm_key",
+// holds a secret, never logged nor printed.
+func IsSensitiveField(name string) bool {
+	return sensitiveFields[name]
+}
+
+// logRequests is the built-in middleware logging every HTTP request sent,
+// retries included, to c.logger at the debug level.
+func (c *Client) logRequests(next Doer) Doer {
+	return DoerFunc(func(r *http.Request) (*http.Response, error) {
+		if c.logger == nil {
+			return next.Do(r)
+		}
+		ctx := r.Context()
+
+		attrs := []slog.Attr{
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+		}
+		if c.logBodies && r.GetBody != nil {
+			if body, err := r.GetBody(); err == nil {
+				b, _ := ioutil.ReadAll(body)
+				body.Close()
+				attrs = append(attrs, slog.String("request_body", c.redact(b)))
+			}
+		}
+
+		start := time.Now()
+		resp, err := next.Do(r)
+		attrs = append(attrs, slog.Duration("duration", time.Since(start)))
+		if err != nil {
+			attrs = append(attrs, slog.String("error", err.Error()))
+			c.logger.LogAttrs(ctx, slog.LevelDebug, "onesignal: request failed", attrs...)
+			return resp, err
+		}
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+
+		if c.logBodies {
+			b, err := ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+			resp.Body = ioutil.NopCloser(bytes.NewReader(b))
+			if err != nil {
+				attrs = append(attrs, slog.String("error", err.Error()))
+				c.logger.LogAttrs(ctx, slog.LevelDebug, "onesignal: request failed", attrs...)
+				return resp, nil
+			}
+			attrs = append(attrs, slog.Int("size", len(b)), slog.String("response_body", c.redact(b)))
+			c.logger.LogAttrs(ctx, slog.LevelDebug, "onesignal: request sent", attrs...)
+			return resp, nil
+		}
+
+		// the size of the response is only known once it has been read
+		resp.Body = &loggedBody{ReadCloser: resp.Body, onClose: func(size int64) {
+			attrs = append(attrs, slog.Int64("size", size))
+			c.logger.LogAttrs(ctx, slog.LevelDebug, "onesignal: request sent", attrs...)
+		}}
+		return resp, nil
+	})
+}
+
+// loggedBody counts the bytes read from a response body and reports them
+// when the body is closed.
+type loggedBody struct {
+	io.ReadCloser
+	size    int64
+	onClose func(size int64)
+	closed  bool
+}
+
+func (b *loggedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.size += int64(n)
+	return n, err
+}
+
+func (b *loggedBody) Close() error {
+	err := b.ReadCloser.Close()
+	if !b.closed {
+		b.closed = true
+		b.onClose(b.size)
+	}
+	return err
+}
+
+// redact returns body with the client's keys and the values of the
+// sensitive JSON fields replaced by REDACTED.
+func (c *Client) redact(body []byte) string {
+	return strings.TrimSpace(string(RedactBody(body, c.AppKey, c.UserKey)))
+}
+
+func redactJSON(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, val := range v {
+			if sensitiveFields[k] {
+				v[k] = redacted
+			} else {
+				v[k] = redactJSON(val)
+			}
+		}
+	case []interface{}:
+		for i, val := range v {
+			v[i] = redactJSON(val)
+		}
+	}
+	return v
+}
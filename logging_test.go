@@ -0,0 +1,151 @@
+package onesignal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// setupLogger makes the test client log to the returned buffer.
+func setupLogger(logBodies bool) *bytes.Buffer {
+	buf := new(bytes.Buffer)
+	logger := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	WithLogger(logger)(client)
+	WithBodyLogging(logBodies)(client)
+	return buf
+}
+
+func decodeLogLine(t *testing.T, buf *bytes.Buffer) map[string]interface{} {
+	var line map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("Couldn't decode log line %q: %v", buf.String(), err)
+	}
+	return line
+}
+
+func TestDo_logsRequests(t *testing.T) {
+	setup()
+	defer teardown()
+	buf := setupLogger(false)
+
+	mux.HandleFunc("/apps", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[]`)
+	})
+
+	client.Apps.List()
+
+	line := decodeLogLine(t, buf)
+	if got, want := line["msg"], "onesignal: request sent"; got != want {
+		t.Errorf("msg is %v, want %v", got, want)
+	}
+	if got, want := line["level"], "DEBUG"; got != want {
+		t.Errorf("level is %v, want %v", got, want)
+	}
+	if got, want := line["method"], "GET"; got != want {
+		t.Errorf("method is %v, want %v", got, want)
+	}
+	if got, want := line["path"], "/apps"; got != want {
+		t.Errorf("path is %v, want %v", got, want)
+	}
+	if got, want := line["status"], float64(200); got != want {
+		t.Errorf("status is %v, want %v", got, want)
+	}
+	if got, want := line["size"], float64(2); got != want {
+		t.Errorf("size is %v, want %v", got, want)
+	}
+	if _, ok := line["duration"]; !ok {
+		t.Errorf("duration should be logged")
+	}
+	if _, ok := line["response_body"]; ok {
+		t.Errorf("response_body should not be logged")
+	}
+}
+
+func TestDo_logsRedactedBodies(t *testing.T) {
+	setup()
+	defer teardown()
+	buf := setupLogger(true)
+
+	mux.HandleFunc("/apps/app-id", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": "app-id", "basic_auth_key": "secret-basic-auth-key"}`)
+	})
+
+	appRequest := &AppRequest{
+		Name:            "Your app 1",
+		APNSP12:         "secret-p12",
+		APNSP12Password: "secret-password",
+		SiteName:        "fake-user-key",
+	}
+	client.Apps.Update("app-id", appRequest)
+
+	line := decodeLogLine(t, buf)
+	for _, secret := range []string{"secret-basic-auth-key", "secret-p12", "secret-password", "fake-user-key"} {
+		if strings.Contains(buf.String(), secret) {
+			t.Errorf("Log output %q should not contain %q", buf.String(), secret)
+		}
+	}
+
+	reqBody, _ := line["request_body"].(string)
+	if !strings.Contains(reqBody, `"name":"Your app 1"`) || !strings.Contains(reqBody, `"apns_p12_password":"REDACTED"`) {
+		t.Errorf("request_body is %q, want the name and a redacted password", reqBody)
+	}
+	respBody, _ := line["response_body"].(string)
+	if !strings.Contains(respBody, `"basic_auth_key":"REDACTED"`) {
+		t.Errorf("response_body is %q, want a redacted basic_auth_key", respBody)
+	}
+}
+
+func TestDo_logsBodiesAndDecodesResponse(t *testing.T) {
+	setup()
+	defer teardown()
+	setupLogger(true)
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"A":"a"}`)
+	})
+
+	req, _ := client.NewRequest("GET", "/", nil, APP)
+	body := new(struct{ A string })
+	client.Do(req, body)
+
+	if got, want := body.A, "a"; got != want {
+		t.Errorf("Response body A = %v, want %v", got, want)
+	}
+}
+
+func TestDo_withoutLogger(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{}`)
+	})
+
+	req, _ := client.NewRequest("GET", "/", nil, APP)
+	if _, err := client.Do(req, nil); err != nil {
+		t.Errorf("Do returned an error: %v", err)
+	}
+}
+
+func TestClient_redact(t *testing.T) {
+	c, _ := NewClient(WithAppKey("fake-app-key"))
+
+	tests := []struct {
+		body string
+		want string
+	}{
+		{`{"apns_p12_password":"pwd","name":"app"}`, `{"apns_p12_password":"REDACTED","name":"app"}`},
+		{`[{"safari_apns_p12":"p12"}]`, `[{"safari_apns_p12":"REDACTED"}]`},
+		{`{"nested":{"basic_auth_key":"key"}}`, `{"nested":{"basic_auth_key":"REDACTED"}}`},
+		{`not json fake-app-key`, `not json REDACTED`},
+	}
+	for _, tt := range tests {
+		if got := c.redact([]byte(tt.body)); got != tt.want {
+			t.Errorf("redact(%q) = %q, want %q", tt.body, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package onesignal
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+)
+
+// A Doer sends an HTTP request and returns an HTTP response. *http.Client
+// is a Doer.
+type Doer interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// DoerFunc is an adapter allowing the use of an ordinary function as a
+// Doer.
+type DoerFunc func(*http.Request) (*http.Response, error)
+
+// Do calls f(r).
+func (f DoerFunc) Do(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// A Middleware wraps the step of Client.Do that sends the request, to add
+// behavior such as logging, metrics or header injection around every API
+// call. It returns a Doer that will usually call next.
+type Middleware func(next Doer) Doer
+
+// Use adds middlewares to the client. The first middleware added is the
+// outermost one. Middlewares are called once per call to Client.Do, before
+// the client's retries and rate limiting take place, and receive the raw
+// response before CheckResponse and JSON decoding.
+//
+// Like the exported fields of Client, the middlewares are not guarded: Use
+// must be called before the client is used concurrently, typically right
+// after NewClient, and never while requests are being sent.
+func (c *Client) Use(middlewares ...Middleware) {
+	c.middlewares = append(c.middlewares, middlewares...)
+}
+
+// WithMiddleware adds middlewares to the client, as Client.Use does.
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(c *Client) error {
+		c.Use(middlewares...)
+		return nil
+	}
+}
+
+// doer returns the chain of middlewares ending with c.Client.
+func (c *Client) doer() Doer {
+	var d Doer = c.Client
+	d = c.logRequests(d)
+	d = c.rateLimit(d)
+	d = c.retry(d)
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		d = c.middlewares[i](d)
+	}
+	return d
+}
+
+// RedactHeader returns a copy of h in which the Authorization header, which
+// holds the AppKey or the UserKey, is redacted. It is meant for middlewares
+// that log or record requests.
+func RedactHeader(h http.Header) http.Header {
+	h = h.Clone()
+	if h.Get("Authorization") != "" {
+		h.Set("Authorization", "REDACTED")
+	}
+	return h
+}
+
+// RedactBody returns a copy of the JSON body in which the values of the
+// fields holding secrets, such as "basic_auth_key" or "apns_p12", and the
+// keys are redacted. Bodies that aren't JSON only have the keys redacted.
+// It is meant for middlewares that log or record requests.
+func RedactBody(body []byte, keys ...string) []byte {
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err == nil {
+		if b, err := json.Marshal(redactJSON(v)); err == nil {
+			body = b
+		}
+	}
+
+	body = bytes.Clone(body)
+	for _, key := range keys {
+		if key != "" {
+			body = bytes.ReplaceAll(body, []byte(key), []byte(redacted))
+		}
+	}
+	return body
+}
@@ -0,0 +1,153 @@
+package onesignal
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestClient_Use(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var calls []string
+	trace := func(name string) Middleware {
+		return func(next Doer) Doer {
+			return DoerFunc(func(r *http.Request) (*http.Response, error) {
+				calls = append(calls, name+" before")
+				resp, err := next.Do(r)
+				calls = append(calls, name+" after")
+				return resp, err
+			})
+		}
+	}
+	client.Use(trace("first"), trace("second"))
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, "send")
+		fmt.Fprint(w, `{}`)
+	})
+
+	req, _ := client.NewRequest("GET", "/", nil, APP)
+	if _, err := client.Do(req, nil); err != nil {
+		t.Fatalf("Do returned an error: %v", err)
+	}
+
+	want := []string{"first before", "second before", "send", "second after", "first after"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("Calls: %v, want %v", calls, want)
+	}
+}
+
+func TestClient_Use_injectsHeader(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.Use(func(next Doer) Doer {
+		return DoerFunc(func(r *http.Request) (*http.Response, error) {
+			r.Header.Set("X-Audit-ID", "audit-123")
+			return next.Do(r)
+		})
+	})
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		testHeader(t, r, "X-Audit-ID", "audit-123")
+		fmt.Fprint(w, `{}`)
+	})
+
+	req, _ := client.NewRequest("GET", "/", nil, APP)
+	client.Do(req, nil)
+}
+
+func TestClient_Use_calledOncePerDoWithRetries(t *testing.T) {
+	setup()
+	defer teardown()
+	client.RetryPolicy = testRetryPolicy()
+
+	calls := 0
+	client.Use(func(next Doer) Doer {
+		return DoerFunc(func(r *http.Request) (*http.Response, error) {
+			calls++
+			return next.Do(r)
+		})
+	})
+
+	attempts := 0
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{}`)
+	})
+
+	req, _ := client.NewRequest("GET", "/", nil, APP)
+	client.Do(req, nil)
+
+	if got, want := calls, 1; got != want {
+		t.Errorf("Middleware calls: %d, want %d", got, want)
+	}
+	if got, want := attempts, 3; got != want {
+		t.Errorf("Attempts: %d, want %d", got, want)
+	}
+}
+
+func TestWithMiddleware(t *testing.T) {
+	mw := func(next Doer) Doer { return next }
+
+	c, err := NewClient(WithMiddleware(mw, mw))
+	if err != nil {
+		t.Fatalf("NewClient returned an error: %v", err)
+	}
+
+	if got, want := len(c.middlewares), 2; got != want {
+		t.Errorf("Middlewares: %d, want %d", got, want)
+	}
+}
+
+func TestRedactHeader(t *testing.T) {
+	h := http.Header{}
+	h.Set("Authorization", "Basic fake-app-key")
+	h.Set("Accept", "application/json")
+
+	got := RedactHeader(h)
+
+	if got, want := got.Get("Authorization"), "REDACTED"; got != want {
+		t.Errorf("Authorization header is %v, want %v", got, want)
+	}
+	if got, want := got.Get("Accept"), "application/json"; got != want {
+		t.Errorf("Accept header is %v, want %v", got, want)
+	}
+	if got, want := h.Get("Authorization"), "Basic fake-app-key"; got != want {
+		t.Errorf("Original Authorization header is %v, want %v", got, want)
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	body := []byte(`{"name":"My app","basic_auth_key":"fake-app-key","players":[{"gcm_key":"secret"}],"url":"https://example.com/?key=fake-user-key"}`)
+
+	got := RedactBody(body, "fake-app-key", "fake-user-key", "")
+
+	var v, want interface{}
+	if err := json.Unmarshal(got, &v); err != nil {
+		t.Fatalf("RedactBody returned invalid JSON %s: %v", got, err)
+	}
+	json.Unmarshal([]byte(`{"name":"My app","basic_auth_key":"REDACTED","players":[{"gcm_key":"REDACTED"}],"url":"https://example.com/?key=REDACTED"}`), &want)
+	if !reflect.DeepEqual(v, want) {
+		t.Errorf("RedactBody returned %s", got)
+	}
+}
+
+func TestRedactBody_notJSON(t *testing.T) {
+	body := []byte("id,key\n1,fake-app-key\n")
+
+	if got, want := string(RedactBody(body, "fake-app-key")), "id,key\n1,REDACTED\n"; got != want {
+		t.Errorf("RedactBody returned %q, want %q", got, want)
+	}
+	if got, want := string(body), "id,key\n1,fake-app-key\n"; got != want {
+		t.Errorf("Original body is %q, want %q", got, want)
+	}
+}
@@ -1,9 +1,12 @@
 package onesignal
 
 import (
+	"context"
+	"encoding/json"
 	"net/http"
 	"net/url"
 	"strconv"
+	"time"
 )
 
 // NotificationsService handles communication with the notification related
@@ -19,8 +22,8 @@ type Notification struct {
 	Failed     int               `json:"failed"`
 	Converted  int               `json:"converted"`
 	Remaining  int               `json:"remaining"`
-	QueuedAt   int               `json:"queued_at"`
-	SendAfter  int               `json:"send_after"`
+	QueuedAt   time.Time         `json:"queued_at"`
+	SendAfter  time.Time         `json:"send_after"`
 	URL        string            `json:"url"`
 	Data       interface{}       `json:"data"`
 	Canceled   bool              `json:"canceled"`
@@ -28,6 +31,54 @@ type Notification struct {
 	Contents   map[string]string `json:"contents"`
 }
 
+// notificationJSON is how a Notification is encoded, with timestamps in Unix
+// seconds.
+type notificationJSON struct {
+	*notification
+	QueuedAt  int64 `json:"queued_at"`
+	SendAfter int64 `json:"send_after"`
+}
+
+// notification has the fields of Notification but not its methods.
+type notification Notification
+
+// MarshalJSON encodes n, with QueuedAt and SendAfter in Unix seconds as in
+// the OneSignal API.
+func (n Notification) MarshalJSON() ([]byte, error) {
+	return json.Marshal(notificationJSON{
+		notification: (*notification)(&n),
+		QueuedAt:     unixSeconds(n.QueuedAt),
+		SendAfter:    unixSeconds(n.SendAfter),
+	})
+}
+
+// UnmarshalJSON decodes n, reading QueuedAt and SendAfter from Unix seconds.
+func (n *Notification) UnmarshalJSON(b []byte) error {
+	aux := notificationJSON{notification: (*notification)(n)}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+	n.QueuedAt = unixTime(aux.QueuedAt)
+	n.SendAfter = unixTime(aux.SendAfter)
+	return nil
+}
+
+// unixTime returns the time of the Unix timestamp secs, where 0 stands for
+// no time at all.
+func unixTime(secs int64) time.Time {
+	if secs == 0 {
+		return time.Time{}
+	}
+	return time.Unix(secs, 0)
+}
+
+func unixSeconds(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
 // NotificationRequest represents a request to create a notification.
 type NotificationRequest struct {
 	AppID                  string            `json:"app_id"`
@@ -62,6 +113,8 @@ type NotificationRequest struct {
 	WPWNSSound             string            `json:"wp_wns_sound,omitempty"`
 	Data                   interface{}       `json:"data,omitempty"`
 	Buttons                interface{}       `json:"buttons,omitempty"`
+	WebButtons             []WebButton       `json:"web_buttons,omitempty"`
+	IOSCategory            string            `json:"ios_category,omitempty"`
 	SmallIcon              string            `json:"small_icon,omitempty"`
 	LargeIcon              string            `json:"large_icon,omitempty"`
 	BigPicture             string            `json:"big_picture,omitempty"`
@@ -74,7 +127,7 @@ type NotificationRequest struct {
 	FirefoxIcon            string            `json:"firefox_icon,omitempty"`
 	URL                    string            `json:"url,omitempty"`
 	SendAfter              string            `json:"send_after,omitempty"`
-	DelayedOption          string            `json:"delayed_option,omitempty"`
+	DelayedOption          DelayedOption     `json:"delayed_option,omitempty"`
 	DeliveryTimeOfDay      string            `json:"delivery_time_of_day,omitempty"`
 	AndroidLEDColor        string            `json:"android_led_color,omitempty"`
 	AndroidAccentColor     string            `json:"android_accent_color,omitempty"`
@@ -98,6 +151,40 @@ type NotificationCreateResponse struct {
 	Errors     interface{} `json:"errors"`
 }
 
+// recipients returns the number of devices explicitly targeted by n.
+func (n *NotificationRequest) recipients() int {
+	return len(n.IncludePlayerIDs) +
+		len(n.IncludeIOSTokens) +
+		len(n.IncludeAndroidRegIDs) +
+		len(n.IncludeWPURIs) +
+		len(n.IncludeWPWNSURIs) +
+		len(n.IncludeAmazonRegIDs) +
+		len(n.IncludeChromeRegIDs) +
+		len(n.IncludeChromeWebRegIDs)
+}
+
+// Err returns an *ErrorResponse describing the Errors OneSignal reported for
+// an accepted notification, such as invalid player IDs, or nil if there are
+// none.
+func (r *NotificationCreateResponse) Err() error {
+	if r.Errors == nil {
+		return nil
+	}
+	raw, err := json.Marshal(r.Errors)
+	if err != nil {
+		return err
+	}
+
+	errResp := &ErrorResponse{StatusCode: http.StatusOK, Body: raw}
+	if err := errResp.decodeErrors(raw); err != nil {
+		return err
+	}
+	if len(errResp.Messages) == 0 && len(errResp.InvalidPlayerIDs) == 0 {
+		return nil
+	}
+	return errResp
+}
+
 // NotificationListOptions specifies the parameters to the
 // NotificationsService.List method
 type NotificationListOptions struct {
@@ -139,6 +226,14 @@ type NotificationDeleteOptions struct {
 // OneSignal API docs:
 // https://documentation.onesignal.com/docs/notifications-view-notifications
 func (s *NotificationsService) List(opt *NotificationListOptions) (*NotificationListResponse, *http.Response, error) {
+	return s.ListWithContext(context.Background(), opt)
+}
+
+// ListWithContext is like List but takes a context.Context that
+// controls the lifetime of the request.
+func (s *NotificationsService) ListWithContext(ctx context.Context, opt *NotificationListOptions) (*NotificationListResponse, *http.Response, error) {
+	ctx = withOperation(ctx, Operation{Name: "notifications.list", AppID: opt.AppID})
+
 	// build the URL with the query string
 	u, err := url.Parse("/notifications")
 	if err != nil {
@@ -151,7 +246,7 @@ func (s *NotificationsService) List(opt *NotificationListOptions) (*Notification
 	u.RawQuery = q.Encode()
 
 	// create the request
-	req, err := s.client.NewRequest("GET", u.String(), nil, APP)
+	req, err := s.client.NewRequestWithContext(ctx, "GET", u.String(), nil, APP)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -170,6 +265,14 @@ func (s *NotificationsService) List(opt *NotificationListOptions) (*Notification
 // OneSignal API docs:
 // https://documentation.onesignal.com/docs/notificationsid-view-notification
 func (s *NotificationsService) Get(notificationID string, opt *NotificationGetOptions) (*Notification, *http.Response, error) {
+	return s.GetWithContext(context.Background(), notificationID, opt)
+}
+
+// GetWithContext is like Get but takes a context.Context that
+// controls the lifetime of the request.
+func (s *NotificationsService) GetWithContext(ctx context.Context, notificationID string, opt *NotificationGetOptions) (*Notification, *http.Response, error) {
+	ctx = withOperation(ctx, Operation{Name: "notifications.get", AppID: opt.AppID})
+
 	// build the URL with the query string
 	u, err := url.Parse("/notifications/" + notificationID)
 	if err != nil {
@@ -180,7 +283,7 @@ func (s *NotificationsService) Get(notificationID string, opt *NotificationGetOp
 	u.RawQuery = q.Encode()
 
 	// create the request
-	req, err := s.client.NewRequest("GET", u.String(), nil, APP)
+	req, err := s.client.NewRequestWithContext(ctx, "GET", u.String(), nil, APP)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -199,6 +302,29 @@ func (s *NotificationsService) Get(notificationID string, opt *NotificationGetOp
 // OneSignal API docs:
 // https://documentation.onesignal.com/docs/notifications-create-notification
 func (s *NotificationsService) Create(opt *NotificationRequest) (*NotificationCreateResponse, *http.Response, error) {
+	return s.CreateWithContext(context.Background(), opt)
+}
+
+// CreateWithContext is like Create but takes a context.Context that
+// controls the lifetime of the request.
+func (s *NotificationsService) CreateWithContext(ctx context.Context, opt *NotificationRequest) (*NotificationCreateResponse, *http.Response, error) {
+	return s.create(ctx, opt, !s.client.skipValidation)
+}
+
+// create creates a notification, validating opt first if validate is set.
+func (s *NotificationsService) create(ctx context.Context, opt *NotificationRequest, validate bool) (*NotificationCreateResponse, *http.Response, error) {
+	op := Operation{Name: "notifications.create"}
+	if opt != nil {
+		op.AppID, op.Recipients = opt.AppID, opt.recipients()
+	}
+	ctx = withOperation(ctx, op)
+
+	if validate {
+		if err := opt.Validate(); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	// build the URL
 	u, err := url.Parse("/notifications")
 	if err != nil {
@@ -206,7 +332,7 @@ func (s *NotificationsService) Create(opt *NotificationRequest) (*NotificationCr
 	}
 
 	// create the request
-	req, err := s.client.NewRequest("POST", u.String(), opt, APP)
+	req, err := s.client.NewRequestWithContext(ctx, "POST", u.String(), opt, APP)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -225,6 +351,18 @@ func (s *NotificationsService) Create(opt *NotificationRequest) (*NotificationCr
 // OneSignal API docs:
 // https://documentation.onesignal.com/docs/notificationsid-track-open
 func (s *NotificationsService) Update(notificationID string, opt *NotificationUpdateOptions) (*SuccessResponse, *http.Response, error) {
+	return s.UpdateWithContext(context.Background(), notificationID, opt)
+}
+
+// UpdateWithContext is like Update but takes a context.Context that
+// controls the lifetime of the request.
+func (s *NotificationsService) UpdateWithContext(ctx context.Context, notificationID string, opt *NotificationUpdateOptions) (*SuccessResponse, *http.Response, error) {
+	op := Operation{Name: "notifications.update"}
+	if opt != nil {
+		op.AppID = opt.AppID
+	}
+	ctx = withOperation(ctx, op)
+
 	// build the URL
 	u, err := url.Parse("/notifications/" + notificationID)
 	if err != nil {
@@ -232,7 +370,7 @@ func (s *NotificationsService) Update(notificationID string, opt *NotificationUp
 	}
 
 	// create the request
-	req, err := s.client.NewRequest("PUT", u.String(), opt, APP)
+	req, err := s.client.NewRequestWithContext(ctx, "PUT", u.String(), opt, APP)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -251,6 +389,18 @@ func (s *NotificationsService) Update(notificationID string, opt *NotificationUp
 // OneSignal API docs:
 // https://documentation.onesignal.com/docs/notificationsid-cancel-notification
 func (s *NotificationsService) Delete(notificationID string, opt *NotificationDeleteOptions) (*SuccessResponse, *http.Response, error) {
+	return s.DeleteWithContext(context.Background(), notificationID, opt)
+}
+
+// DeleteWithContext is like Delete but takes a context.Context that
+// controls the lifetime of the request.
+func (s *NotificationsService) DeleteWithContext(ctx context.Context, notificationID string, opt *NotificationDeleteOptions) (*SuccessResponse, *http.Response, error) {
+	op := Operation{Name: "notifications.delete"}
+	if opt != nil {
+		op.AppID = opt.AppID
+	}
+	ctx = withOperation(ctx, op)
+
 	// build the URL
 	u, err := url.Parse("/notifications/" + notificationID)
 	if err != nil {
@@ -258,7 +408,7 @@ func (s *NotificationsService) Delete(notificationID string, opt *NotificationDe
 	}
 
 	// create the request
-	req, err := s.client.NewRequest("DELETE", u.String(), opt, APP)
+	req, err := s.client.NewRequestWithContext(ctx, "DELETE", u.String(), opt, APP)
 	if err != nil {
 		return nil, nil, err
 	}
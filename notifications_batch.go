@@ -0,0 +1,159 @@
+package onesignal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// MaxPlayerIDsPerNotification is the maximum number of include_player_ids
+// a single notification may target.
+const MaxPlayerIDsPerNotification = 2000
+
+const defaultBatchConcurrency = 4
+
+// BatchOptions specifies the parameters to the
+// NotificationsService.CreateBatched method
+type BatchOptions struct {
+	// BatchSize is the number of player IDs per notification. It defaults
+	// to, and can't exceed, MaxPlayerIDsPerNotification.
+	BatchSize int
+
+	// Concurrency is the maximum number of notifications created at the
+	// same time. It defaults to 4.
+	Concurrency int
+}
+
+// NotificationBatchResponse aggregates the results of the
+// NotificationsService.CreateBatched method
+type NotificationBatchResponse struct {
+	// IDs are the IDs of the notifications created, in batch order.
+	IDs []string
+
+	// Recipients is the total number of recipients of the notifications.
+	Recipients int
+
+	// Errors lists the batches that failed or were partially rejected,
+	// e.g. because of invalid player IDs.
+	Errors []*BatchError
+}
+
+// BatchError reports the error of one batch of a
+// NotificationsService.CreateBatched call.
+type BatchError struct {
+	// Batch is the index of the batch, starting at 0.
+	Batch int
+
+	// PlayerIDs are the player IDs targeted by the batch.
+	PlayerIDs []string
+
+	// Err is the error returned by NotificationsService.Create, or the
+	// errors reported in NotificationCreateResponse.Errors.
+	Err error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("batch %d (%d player ids): %v", e.Batch, len(e.PlayerIDs), e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// CreateBatched creates a notification for every batch of
+// opt.IncludePlayerIDs, so that each one stays under
+// MaxPlayerIDsPerNotification. The batches are sent concurrently.
+//
+// Unless the client was created with WithRequestValidation(false), opt is
+// validated once before it is split: if it is invalid, the
+// *ValidationError is returned and no batch is sent.
+//
+// The returned NotificationBatchResponse is never nil. The error is nil if
+// every batch was accepted without errors, otherwise it joins all the
+// BatchErrors listed in the response.
+func (s *NotificationsService) CreateBatched(ctx context.Context, opt *NotificationRequest, batchOpt *BatchOptions) (*NotificationBatchResponse, error) {
+	batchSize, concurrency := MaxPlayerIDsPerNotification, defaultBatchConcurrency
+	if batchOpt != nil {
+		if batchOpt.BatchSize > 0 {
+			batchSize = batchOpt.BatchSize
+		}
+		if batchOpt.Concurrency > 0 {
+			concurrency = batchOpt.Concurrency
+		}
+	}
+	batchRes := &NotificationBatchResponse{}
+	if opt == nil || len(opt.IncludePlayerIDs) == 0 {
+		return batchRes, errors.New("onesignal: CreateBatched requires include_player_ids")
+	}
+	if batchSize > MaxPlayerIDsPerNotification {
+		return batchRes, fmt.Errorf("onesignal: batch size %d exceeds the maximum of %d", batchSize, MaxPlayerIDsPerNotification)
+	}
+
+	// validate the request once, rather than once per batch
+	if !s.client.skipValidation {
+		if err := opt.Validate(); err != nil {
+			return batchRes, err
+		}
+	}
+
+	// split the recipients
+	var batches [][]string
+	ids := opt.IncludePlayerIDs
+	for len(ids) > 0 {
+		n := batchSize
+		if n > len(ids) {
+			n = len(ids)
+		}
+		batches = append(batches, ids[:n:n])
+		ids = ids[n:]
+	}
+
+	// send the batches
+	results := make([]*NotificationCreateResponse, len(batches))
+	errs := make([]error, len(batches))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, batch := range batches {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, batch []string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			req := *opt
+			req.IncludePlayerIDs = batch
+			createRes, _, err := s.create(ctx, &req, false)
+			if err == nil {
+				err = createRes.Err()
+			}
+			results[i], errs[i] = createRes, err
+		}(i, batch)
+	}
+	wg.Wait()
+
+	// aggregate the results
+	var joined []error
+	for i, createRes := range results {
+		if createRes != nil {
+			if createRes.ID != "" {
+				batchRes.IDs = append(batchRes.IDs, createRes.ID)
+			}
+			batchRes.Recipients += createRes.Recipients
+		}
+		if errs[i] != nil {
+			batchErr := &BatchError{Batch: i, PlayerIDs: batches[i], Err: errs[i]}
+			batchRes.Errors = append(batchRes.Errors, batchErr)
+			joined = append(joined, batchErr)
+		}
+	}
+
+	return batchRes, errors.Join(joined...)
+}
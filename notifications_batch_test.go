@@ -0,0 +1,243 @@
+package onesignal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func playerIDs(n int) []string {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("player-%d", i)
+	}
+	return ids
+}
+
+func TestNotificationsService_CreateBatched(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var mu sync.Mutex
+	var batchSizes []int
+	mux.HandleFunc("/notifications", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		req := new(NotificationRequest)
+		json.NewDecoder(r.Body).Decode(req)
+		if got, want := req.Contents["en"], "English message"; got != want {
+			t.Errorf("Contents: %v, want %v", got, want)
+		}
+
+		mu.Lock()
+		batchSizes = append(batchSizes, len(req.IncludePlayerIDs))
+		mu.Unlock()
+
+		fmt.Fprintf(w, `{"id": "notif-%s", "recipients": %d}`, req.IncludePlayerIDs[0], len(req.IncludePlayerIDs))
+	})
+
+	notificationRequest := &NotificationRequest{
+		AppID:            "id123",
+		Contents:         map[string]string{"en": "English message"},
+		IncludePlayerIDs: playerIDs(5000),
+	}
+	batchRes, err := client.Notifications.CreateBatched(context.Background(), notificationRequest, nil)
+	if err != nil {
+		t.Fatalf("CreateBatched returned an error: %v", err)
+	}
+
+	sort.Ints(batchSizes)
+	if got, want := batchSizes, []int{1000, 2000, 2000}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Batch sizes: %v, want %v", got, want)
+	}
+	want := &NotificationBatchResponse{
+		IDs:        []string{"notif-player-0", "notif-player-2000", "notif-player-4000"},
+		Recipients: 5000,
+	}
+	if !reflect.DeepEqual(batchRes, want) {
+		t.Errorf("CreateBatched returned %+v, want %+v", batchRes, want)
+	}
+	if got, want := len(notificationRequest.IncludePlayerIDs), 5000; got != want {
+		t.Errorf("The request should not be modified, got %d player ids, want %d", got, want)
+	}
+}
+
+func TestNotificationsService_CreateBatched_errors(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/notifications", func(w http.ResponseWriter, r *http.Request) {
+		req := new(NotificationRequest)
+		json.NewDecoder(r.Body).Decode(req)
+
+		switch req.IncludePlayerIDs[0] {
+		case "player-0":
+			fmt.Fprint(w, `{"id": "notif-0", "recipients": 1, "errors": {"invalid_player_ids": ["player-1"]}}`)
+		case "player-2":
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"errors": ["Something went wrong"]}`)
+		default:
+			fmt.Fprint(w, `{"id": "notif-4", "recipients": 1}`)
+		}
+	})
+
+	notificationRequest := &NotificationRequest{
+		AppID:            "id123",
+		Contents:         map[string]string{"en": "English message"},
+		IncludePlayerIDs: playerIDs(5),
+	}
+	batchRes, err := client.Notifications.CreateBatched(context.Background(), notificationRequest, &BatchOptions{BatchSize: 2, Concurrency: 1})
+	if err == nil {
+		t.Fatalf("CreateBatched should return an error")
+	}
+	if !errors.Is(err, ErrInvalidPlayerIDs) {
+		t.Errorf("Error %v should match ErrInvalidPlayerIDs", err)
+	}
+
+	if got, want := batchRes.IDs, []string{"notif-0", "notif-4"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("IDs: %v, want %v", got, want)
+	}
+	if got, want := batchRes.Recipients, 2; got != want {
+		t.Errorf("Recipients: %v, want %v", got, want)
+	}
+	if got, want := len(batchRes.Errors), 2; got != want {
+		t.Fatalf("Errors: got %d, want %d", got, want)
+	}
+
+	invalid := batchRes.Errors[0]
+	if got, want := invalid.Batch, 0; got != want {
+		t.Errorf("Errors[0].Batch: %v, want %v", got, want)
+	}
+	var errResp *ErrorResponse
+	if !errors.As(invalid, &errResp) || !reflect.DeepEqual(errResp.InvalidPlayerIDs, []string{"player-1"}) {
+		t.Errorf("Errors[0] should list the invalid player ids, got %v", invalid)
+	}
+
+	failed := batchRes.Errors[1]
+	if got, want := failed.Batch, 1; got != want {
+		t.Errorf("Errors[1].Batch: %v, want %v", got, want)
+	}
+	if got, want := failed.PlayerIDs, []string{"player-2", "player-3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Errors[1].PlayerIDs: %v, want %v", got, want)
+	}
+}
+
+func TestNotificationsService_CreateBatched_concurrency(t *testing.T) {
+	setup()
+	defer teardown()
+
+	const concurrency, batches = 3, 9
+
+	// the handlers hold the requests until the test releases them, once
+	// concurrency of them are in flight
+	arrived, release := make(chan struct{}), make(chan struct{})
+	var current, max int32
+	mux.HandleFunc("/notifications", func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&current, 1)
+		for {
+			m := atomic.LoadInt32(&max)
+			if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+				break
+			}
+		}
+		arrived <- struct{}{}
+		<-release
+		atomic.AddInt32(&current, -1)
+		fmt.Fprint(w, `{"id": "notif", "recipients": 1}`)
+	})
+
+	notificationRequest := &NotificationRequest{
+		AppID:            "id123",
+		Contents:         map[string]string{"en": "English message"},
+		IncludePlayerIDs: playerIDs(batches),
+	}
+	type result struct {
+		res *NotificationBatchResponse
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		res, err := client.Notifications.CreateBatched(context.Background(), notificationRequest, &BatchOptions{BatchSize: 1, Concurrency: concurrency})
+		done <- result{res, err}
+	}()
+
+	for sent := 0; sent < batches; sent += concurrency {
+		for i := 0; i < concurrency; i++ {
+			select {
+			case <-arrived:
+			case <-time.After(5 * time.Second):
+				t.Fatalf("%d requests in flight, want %d", atomic.LoadInt32(&current), concurrency)
+			}
+		}
+		for i := 0; i < concurrency; i++ {
+			release <- struct{}{}
+		}
+	}
+
+	r := <-done
+	if r.err != nil {
+		t.Fatalf("CreateBatched returned an error: %v", r.err)
+	}
+	if got, want := len(r.res.IDs), batches; got != want {
+		t.Errorf("CreateBatched created %d notifications, want %d", got, want)
+	}
+	if got := atomic.LoadInt32(&max); got != concurrency {
+		t.Errorf("Maximum concurrent requests: %d, want %d", got, concurrency)
+	}
+}
+
+func TestNotificationsService_CreateBatched_invalid(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/notifications", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("no batch should be sent")
+	})
+
+	// no contents
+	notificationRequest := &NotificationRequest{
+		AppID:            "id123",
+		IncludePlayerIDs: playerIDs(5),
+	}
+	batchRes, err := client.Notifications.CreateBatched(context.Background(), notificationRequest, &BatchOptions{BatchSize: 1})
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("CreateBatched returned %v, want a *ValidationError", err)
+	}
+	if len(validationErr.Errors) != 1 || validationErr.Errors[0].Field != "contents" {
+		t.Errorf("CreateBatched returned %v, want a single contents error", err)
+	}
+	if len(batchRes.Errors) != 0 {
+		t.Errorf("CreateBatched reported batch errors: %v", batchRes.Errors)
+	}
+}
+
+func TestNotificationsService_CreateBatched_invalidBatchSize(t *testing.T) {
+	notificationRequest := &NotificationRequest{
+		AppID:            "id123",
+		IncludePlayerIDs: playerIDs(1),
+	}
+	c, _ := NewClient()
+
+	_, err := c.Notifications.CreateBatched(context.Background(), notificationRequest, &BatchOptions{BatchSize: MaxPlayerIDsPerNotification + 1})
+	if err == nil {
+		t.Errorf("CreateBatched should return an error")
+	}
+
+	_, err = c.Notifications.CreateBatched(context.Background(), &NotificationRequest{AppID: "id123"}, nil)
+	if err == nil {
+		t.Errorf("CreateBatched without player ids should return an error")
+	}
+
+	batchRes, err := c.Notifications.CreateBatched(context.Background(), nil, nil)
+	if err == nil || batchRes == nil {
+		t.Errorf("CreateBatched with a nil request returned %v, %v, want an empty response and an error", batchRes, err)
+	}
+}
@@ -0,0 +1,130 @@
+package onesignal
+
+import "fmt"
+
+// Maximum number of action buttons per platform. Notifications targeting
+// several platforms are held to the lowest limit among them.
+const (
+	MaxAndroidButtons = 3
+	MaxIOSButtons     = 4
+	MaxWebButtons     = 2
+)
+
+// Button is an action button of a mobile notification, to be set in
+// NotificationRequest.Buttons as a []Button. The app is told the ID of the
+// button clicked.
+type Button struct {
+	ID   string `json:"id"`
+	Text string `json:"text"`
+
+	// Icon is the name of an Android drawable resource.
+	Icon string `json:"icon,omitempty"`
+}
+
+// WebButton is an action button of a web push notification, set in
+// NotificationRequest.WebButtons.
+type WebButton struct {
+	ID   string `json:"id"`
+	Text string `json:"text"`
+
+	// Icon is the URL of the button icon.
+	Icon string `json:"icon,omitempty"`
+
+	// URL is opened when the button is clicked.
+	URL string `json:"url,omitempty"`
+}
+
+// Action describes an action button of an interactive notification once for
+// every platform. See NotificationRequest.SetActions.
+type Action struct {
+	ID   string
+	Text string
+
+	// Icon is the name of the Android drawable resource shown on mobile.
+	Icon string
+
+	// WebIcon is the URL of the icon shown on the web.
+	WebIcon string
+
+	// URL is opened when the button is clicked on the web. On mobile, the
+	// app handles the click using the ID.
+	URL string
+}
+
+// SetActions makes n an interactive notification with the given action
+// buttons. Buttons is set if n targets mobile platforms and WebButtons if it
+// targets the web, where a notification targeting no platform in particular
+// targets them all. An error is returned, and n is left unchanged, if there
+// are more actions than the targeted platforms support.
+func (n *NotificationRequest) SetActions(actions ...Action) error {
+	var buttons []Button
+	var webButtons []WebButton
+	if n.targetsMobile() {
+		buttons = make([]Button, len(actions))
+		for i, a := range actions {
+			buttons[i] = Button{ID: a.ID, Text: a.Text, Icon: a.Icon}
+		}
+	}
+	if n.targetsWeb() {
+		webButtons = make([]WebButton, len(actions))
+		for i, a := range actions {
+			webButtons[i] = WebButton{ID: a.ID, Text: a.Text, Icon: a.WebIcon, URL: a.URL}
+		}
+	}
+
+	if fieldErr := n.checkButtonCounts(len(buttons), len(webButtons)); fieldErr != nil {
+		return fieldErr
+	}
+	n.Buttons = nil
+	if buttons != nil {
+		n.Buttons = buttons
+	}
+	n.WebButtons = webButtons
+	return nil
+}
+
+// targetsAllPlatforms reports whether n doesn't restrict the platforms it
+// is sent to.
+func (n *NotificationRequest) targetsAllPlatforms() bool {
+	return !(n.IsIOS || n.IsAndroid || n.IsWP || n.IsADM || n.IsChrome ||
+		n.IsChromeWeb || n.IsSafari || n.IsAnyWeb)
+}
+
+func (n *NotificationRequest) targetsMobile() bool {
+	return n.targetsAllPlatforms() || n.IsIOS || n.IsAndroid || n.IsADM
+}
+
+func (n *NotificationRequest) targetsIOS() bool {
+	return n.targetsAllPlatforms() || n.IsIOS
+}
+
+func (n *NotificationRequest) targetsAndroid() bool {
+	return n.targetsAllPlatforms() || n.IsAndroid || n.IsADM
+}
+
+func (n *NotificationRequest) targetsWeb() bool {
+	return n.targetsAllPlatforms() || n.IsChromeWeb || n.IsAnyWeb
+}
+
+// checkButtons checks that n doesn't have more buttons than the platforms
+// it targets support. Buttons set to something other than a []Button are
+// not checked, nor is a nil request, which has no buttons.
+func (n *NotificationRequest) checkButtons() *FieldError {
+	if n == nil {
+		return nil
+	}
+	buttons, _ := n.Buttons.([]Button)
+	return n.checkButtonCounts(len(buttons), len(n.WebButtons))
+}
+
+func (n *NotificationRequest) checkButtonCounts(buttons, webButtons int) *FieldError {
+	switch {
+	case buttons > MaxAndroidButtons && n.targetsAndroid():
+		return &FieldError{Field: "buttons", Message: fmt.Sprintf("%d buttons, Android supports at most %d", buttons, MaxAndroidButtons)}
+	case buttons > MaxIOSButtons && n.targetsIOS():
+		return &FieldError{Field: "buttons", Message: fmt.Sprintf("%d buttons, iOS supports at most %d", buttons, MaxIOSButtons)}
+	case webButtons > MaxWebButtons:
+		return &FieldError{Field: "web_buttons", Message: fmt.Sprintf("%d buttons, web push supports at most %d", webButtons, MaxWebButtons)}
+	}
+	return nil
+}
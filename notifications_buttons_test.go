@@ -0,0 +1,171 @@
+package onesignal
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var sampleActions = []Action{
+	{ID: "accept", Text: "Accept", Icon: "ic_accept", WebIcon: "https://example.com/accept.png", URL: "https://example.com/accept"},
+	{ID: "decline", Text: "Decline"},
+}
+
+func TestNotificationRequest_SetActions(t *testing.T) {
+	n := &NotificationRequest{AppID: "id123"}
+	if err := n.SetActions(sampleActions...); err != nil {
+		t.Fatalf("SetActions returned an error: %v", err)
+	}
+
+	wantButtons := []Button{
+		{ID: "accept", Text: "Accept", Icon: "ic_accept"},
+		{ID: "decline", Text: "Decline"},
+	}
+	if !reflect.DeepEqual(n.Buttons, wantButtons) {
+		t.Errorf("Buttons: %+v, want %+v", n.Buttons, wantButtons)
+	}
+	wantWebButtons := []WebButton{
+		{ID: "accept", Text: "Accept", Icon: "https://example.com/accept.png", URL: "https://example.com/accept"},
+		{ID: "decline", Text: "Decline"},
+	}
+	if !reflect.DeepEqual(n.WebButtons, wantWebButtons) {
+		t.Errorf("WebButtons: %+v, want %+v", n.WebButtons, wantWebButtons)
+	}
+}
+
+func TestNotificationRequest_SetActions_platforms(t *testing.T) {
+	mobile := &NotificationRequest{IsIOS: true}
+	mobile.SetActions(sampleActions...)
+	if mobile.Buttons == nil {
+		t.Errorf("Buttons should be set for iOS")
+	}
+	if mobile.WebButtons != nil {
+		t.Errorf("WebButtons should not be set for iOS")
+	}
+
+	web := &NotificationRequest{IsChromeWeb: true, Buttons: []Button{{ID: "old"}}}
+	web.SetActions(sampleActions...)
+	if web.Buttons != nil {
+		t.Errorf("Buttons should not be set for Chrome web")
+	}
+	if web.WebButtons == nil {
+		t.Errorf("WebButtons should be set for Chrome web")
+	}
+
+	b, _ := json.Marshal(web)
+	if strings.Contains(string(b), `"buttons"`) {
+		t.Errorf("Request should not contain buttons: %s", b)
+	}
+}
+
+func TestNotificationRequest_SetActions_tooMany(t *testing.T) {
+	actions := []Action{{ID: "1"}, {ID: "2"}, {ID: "3"}, {ID: "4"}}
+
+	tests := []struct {
+		name    string
+		n       *NotificationRequest
+		actions []Action
+		wantErr bool
+	}{
+		{"ios and android, 3 buttons", &NotificationRequest{IsIOS: true, IsAndroid: true}, actions[:3], false},
+		{"android, 4 buttons", &NotificationRequest{IsAndroid: true}, actions, true},
+		{"ios, 4 buttons", &NotificationRequest{IsIOS: true}, actions, false},
+		{"web, 3 buttons", &NotificationRequest{IsChromeWeb: true}, actions[:3], true},
+		{"all platforms, 3 buttons with web", &NotificationRequest{}, actions[:3], true},
+	}
+
+	for _, tt := range tests {
+		err := tt.n.SetActions(tt.actions...)
+		if got := err != nil; got != tt.wantErr {
+			t.Errorf("%s: SetActions returned %v, want error: %v", tt.name, err, tt.wantErr)
+		}
+		if err != nil && (tt.n.Buttons != nil || tt.n.WebButtons != nil) {
+			t.Errorf("%s: request should be left unchanged", tt.name)
+		}
+	}
+}
+
+func TestNotificationRequest_checkButtonCounts(t *testing.T) {
+	tests := []struct {
+		name    string
+		n       *NotificationRequest
+		buttons int
+		wantErr string
+	}{
+		{"android, 5 buttons", &NotificationRequest{IsAndroid: true}, 5, "Android supports at most 3"},
+		{"ios, 5 buttons", &NotificationRequest{IsIOS: true}, 5, "iOS supports at most 4"},
+		{"windows phone, 5 buttons", &NotificationRequest{IsWP: true}, 5, ""},
+	}
+
+	for _, tt := range tests {
+		err := tt.n.checkButtonCounts(tt.buttons, 0)
+		if tt.wantErr == "" && err != nil {
+			t.Errorf("%s: checkButtonCounts returned %v, want no error", tt.name, err)
+		}
+		if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("%s: checkButtonCounts returned %v, want an error containing %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestNotificationsService_Create_buttons(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/notifications", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		want := map[string]interface{}{
+			"app_id":            "id123",
+			"contents":          map[string]interface{}{"en": "English message"},
+			"included_segments": []interface{}{"All"},
+			"ios_category":      "invite",
+			"buttons": []interface{}{
+				map[string]interface{}{"id": "accept", "text": "Accept"},
+			},
+			"web_buttons": []interface{}{
+				map[string]interface{}{"id": "accept", "text": "Accept", "url": "https://example.com/accept"},
+			},
+		}
+		if !reflect.DeepEqual(body, want) {
+			t.Errorf("Request body: %+v, want %+v", body, want)
+		}
+		w.Write([]byte(`{"id": "notif-fake-id", "recipients": 1}`))
+	})
+
+	_, _, err := client.Notifications.Create(&NotificationRequest{
+		AppID:            "id123",
+		Contents:         map[string]string{"en": "English message"},
+		IncludedSegments: []string{"All"},
+		Buttons:          []Button{{ID: "accept", Text: "Accept"}},
+		WebButtons:       []WebButton{{ID: "accept", Text: "Accept", URL: "https://example.com/accept"}},
+		IOSCategory:      "invite",
+	})
+	if err != nil {
+		t.Errorf("Create returned an error: %v", err)
+	}
+}
+
+func TestNotificationsService_Create_tooManyButtons(t *testing.T) {
+	setup()
+	defer teardown()
+
+	requestSent := false
+	mux.HandleFunc("/notifications", func(w http.ResponseWriter, r *http.Request) {
+		requestSent = true
+	})
+
+	_, _, err := client.Notifications.Create(&NotificationRequest{
+		AppID:       "id123",
+		IsChromeWeb: true,
+		WebButtons:  []WebButton{{ID: "1"}, {ID: "2"}, {ID: "3"}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "web_buttons") {
+		t.Errorf("Create returned %v, want a web_buttons error", err)
+	}
+	if requestSent {
+		t.Errorf("Request should not have been sent")
+	}
+}
@@ -0,0 +1,165 @@
+package onesignal
+
+import (
+	"context"
+	"io"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// HistoryEvent selects the players listed by NotificationsService.History.
+type HistoryEvent string
+
+const (
+	// HistorySent lists the players the notification was sent to.
+	HistorySent HistoryEvent = "sent"
+
+	// HistoryClicked lists the players who clicked the notification.
+	HistoryClicked HistoryEvent = "clicked"
+)
+
+// NotificationHistoryOptions specifies the parameters to the
+// NotificationsService.History method
+type NotificationHistoryOptions struct {
+	AppID  string       `json:"app_id"`
+	Events HistoryEvent `json:"events"`
+
+	// Email, if set, is sent a link to the CSV file when it is ready.
+	Email string `json:"email,omitempty"`
+}
+
+// NotificationHistoryResponse wraps the standard http.Response for the
+// NotificationsService.History method
+type NotificationHistoryResponse struct {
+	Success bool `json:"success"`
+
+	// DestinationURL is where the CSV file can be downloaded from, once
+	// OneSignal has generated it. See NotificationsService.HistoryRecords.
+	DestinationURL string `json:"destination_url"`
+}
+
+// HistoryRecordsOptions specifies the parameters to the
+// NotificationsService.HistoryRecords method
+type HistoryRecordsOptions struct {
+	// Poll controls how the history file is polled until OneSignal has
+	// generated it: MaxAttempts bounds the number of downloads attempted,
+	// which are spaced out with an exponential backoff. Retryable and
+	// RetryNonIdempotent are ignored. If nil, the file is polled up to 30
+	// times, starting after 1s and backing off up to 30s.
+	Poll *RetryPolicy
+
+	// HTTPClient downloads the history file. It defaults to the HTTP client
+	// of the Client.
+	HTTPClient *http.Client
+}
+
+// HistoryRecord is a row of a notification history CSV file.
+type HistoryRecord struct {
+	PlayerID       string
+	ExternalUserID string
+
+	// DeviceType is one of the DeviceType* constants, or -1 if the file
+	// doesn't tell.
+	DeviceType int
+
+	// Fields holds every column of the row, by name.
+	Fields map[string]string
+}
+
+// History requests a CSV file listing the players a notification was sent
+// to, or who clicked it. The file is generated asynchronously: the response
+// tells where to download it from when it is ready, which HistoryRecords
+// does.
+//
+// OneSignal API docs:
+// https://documentation.onesignal.com/reference/notification-history
+func (s *NotificationsService) History(notificationID string, opt *NotificationHistoryOptions) (*NotificationHistoryResponse, *http.Response, error) {
+	return s.HistoryWithContext(context.Background(), notificationID, opt)
+}
+
+// HistoryWithContext is like History but takes a context.Context that
+// controls the lifetime of the request.
+func (s *NotificationsService) HistoryWithContext(ctx context.Context, notificationID string, opt *NotificationHistoryOptions) (*NotificationHistoryResponse, *http.Response, error) {
+	op := Operation{Name: "notifications.history"}
+	if opt != nil {
+		op.AppID = opt.AppID
+	}
+	ctx = withOperation(ctx, op)
+
+	// build the URL
+	u, err := url.Parse("/notifications/" + notificationID + "/history")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// create the request
+	req, err := s.client.NewRequestWithContext(ctx, "POST", u.String(), opt, APP)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	historyRes := &NotificationHistoryResponse{}
+	resp, err := s.client.Do(req, historyRes)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return historyRes, resp, err
+}
+
+// HistoryRecords returns an iterator over the rows of the notification
+// history CSV file at destinationURL, as returned by History. It waits for
+// OneSignal to generate the file, polling it as set by opt, which may be
+// nil, then downloads it, and gunzips it if needed, as the iteration goes.
+// The iteration stops after the first error, which is the context's error
+// if ctx is done.
+//
+//	for record, err := range client.Notifications.HistoryRecords(ctx, historyRes.DestinationURL, nil) {
+//		if err != nil {
+//			return err
+//		}
+//		// ...
+//	}
+func (s *NotificationsService) HistoryRecords(ctx context.Context, destinationURL string, opt *HistoryRecordsOptions) iter.Seq2[HistoryRecord, error] {
+	return func(yield func(HistoryRecord, error) bool) {
+		httpClient, poll := s.client.Client, defaultPollPolicy()
+		if opt != nil && opt.HTTPClient != nil {
+			httpClient = opt.HTTPClient
+		}
+		if opt != nil && opt.Poll != nil {
+			poll = opt.Poll
+		}
+		f, err := pollCSV(ctx, httpClient, destinationURL, poll)
+		if err != nil {
+			yield(HistoryRecord{}, err)
+			return
+		}
+		defer f.Close()
+
+		for {
+			row, err := f.next()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				yield(HistoryRecord{}, err)
+				return
+			}
+
+			record := HistoryRecord{
+				PlayerID:       f.get(row, "player_id"),
+				ExternalUserID: f.get(row, "external_user_id"),
+				DeviceType:     -1,
+				Fields:         f.fields(row),
+			}
+			if deviceType, err := strconv.Atoi(f.get(row, "device_type")); err == nil {
+				record.DeviceType = deviceType
+			}
+			if !yield(record, nil) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package onesignal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNotificationsService_History(t *testing.T) {
+	setup()
+	defer teardown()
+
+	requestSent := false
+
+	notifID := "notif-fake-id"
+	opt := &NotificationHistoryOptions{
+		AppID:  "id123",
+		Events: HistoryClicked,
+		Email:  "jane@example.com",
+	}
+
+	mux.HandleFunc("/notifications/"+notifID+"/history", func(w http.ResponseWriter, r *http.Request) {
+		requestSent = true
+
+		testMethod(t, r, "POST")
+		testHeader(t, r, "Authorization", "Basic "+client.AppKey)
+
+		testBody(t, r, &NotificationHistoryOptions{}, opt)
+
+		fmt.Fprint(w, `{
+			"success": true,
+			"destination_url": "https://onesignal-test.s3.amazonaws.com/csv_exports/history.csv"
+		}`)
+	})
+
+	historyRes, _, err := client.Notifications.History(notifID, opt)
+	if err != nil {
+		t.Errorf("History returned an error: %v", err)
+	}
+
+	want := &NotificationHistoryResponse{
+		Success:        true,
+		DestinationURL: "https://onesignal-test.s3.amazonaws.com/csv_exports/history.csv",
+	}
+	if !reflect.DeepEqual(historyRes, want) {
+		t.Errorf("History returned %+v, want %+v", historyRes, want)
+	}
+
+	if requestSent == false {
+		t.Errorf("Request has not been sent")
+	}
+}
+
+// fastPoll polls the CSV files without waiting in tests.
+var fastPoll = &RetryPolicy{MaxAttempts: 5, BaseDelay: time.Millisecond}
+
+func TestNotificationsService_HistoryRecords(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/csv_exports/history.csv", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "" {
+			t.Errorf("Authorization header should not be sent, got %q", got)
+		}
+		fmt.Fprint(w, "player_id,external_user_id,device_type\n"+
+			"playerid1,user1,0\n"+
+			"playerid2,,1\n")
+	})
+
+	var records []HistoryRecord
+	for record, err := range client.Notifications.HistoryRecords(context.Background(), server.URL+"/csv_exports/history.csv", nil) {
+		if err != nil {
+			t.Fatalf("HistoryRecords returned an error: %v", err)
+		}
+		records = append(records, record)
+	}
+
+	want := []HistoryRecord{
+		{
+			PlayerID:       "playerid1",
+			ExternalUserID: "user1",
+			DeviceType:     DeviceTypeIOS,
+			Fields:         map[string]string{"player_id": "playerid1", "external_user_id": "user1", "device_type": "0"},
+		},
+		{
+			PlayerID:   "playerid2",
+			DeviceType: DeviceTypeAndroid,
+			Fields:     map[string]string{"player_id": "playerid2", "external_user_id": "", "device_type": "1"},
+		},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("HistoryRecords returned %+v, want %+v", records, want)
+	}
+}
+
+func TestNotificationsService_HistoryRecords_polling(t *testing.T) {
+	setup()
+	defer teardown()
+
+	polls := 0
+	mux.HandleFunc("/csv_exports/history.csv", func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		switch polls {
+		case 1:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `<Error><Code>NoSuchKey</Code></Error>`)
+		case 2:
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `<Error><Code>AccessDenied</Code></Error>`)
+		default:
+			fmt.Fprint(w, "player_id\nplayerid1\n")
+		}
+	})
+
+	var records []HistoryRecord
+	for record, err := range client.Notifications.HistoryRecords(context.Background(), server.URL+"/csv_exports/history.csv", &HistoryRecordsOptions{Poll: fastPoll}) {
+		if err != nil {
+			t.Fatalf("HistoryRecords returned an error: %v", err)
+		}
+		records = append(records, record)
+	}
+	if polls != 3 {
+		t.Errorf("HistoryRecords polled %d times, want 3", polls)
+	}
+	if len(records) != 1 || records[0].PlayerID != "playerid1" {
+		t.Errorf("HistoryRecords returned %+v", records)
+	}
+}
+
+func TestNotificationsService_HistoryRecords_notReady(t *testing.T) {
+	setup()
+	defer teardown()
+
+	polls := 0
+	mux.HandleFunc("/csv_exports/history.csv", func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `<Error><Code>NoSuchKey</Code></Error>`)
+	})
+
+	n := 0
+	for _, err := range client.Notifications.HistoryRecords(context.Background(), server.URL+"/csv_exports/history.csv", &HistoryRecordsOptions{Poll: fastPoll}) {
+		n++
+		if !errors.Is(err, ErrNotFound) {
+			t.Errorf("HistoryRecords returned %v, want %v", err, ErrNotFound)
+		}
+	}
+	if n != 1 {
+		t.Errorf("HistoryRecords yielded %d times, want 1", n)
+	}
+	if polls != fastPoll.MaxAttempts {
+		t.Errorf("HistoryRecords polled %d times, want %d", polls, fastPoll.MaxAttempts)
+	}
+}
@@ -0,0 +1,81 @@
+package onesignal
+
+import (
+	"fmt"
+	"time"
+)
+
+// DelayedOption is how the delivery of a notification is spread over time
+// for each user.
+type DelayedOption string
+
+const (
+	// DelayedOptionTimezone delivers the notification at
+	// NotificationRequest.DeliveryTimeOfDay in the timezone of each user.
+	DelayedOptionTimezone DelayedOption = "timezone"
+
+	// DelayedOptionLastActive delivers the notification at the time of day
+	// each user is the most likely to open it.
+	DelayedOptionLastActive DelayedOption = "last-active"
+)
+
+// sendAfterLayout is the format of NotificationRequest.SendAfter.
+const sendAfterLayout = "2006-01-02 15:04:05 GMT-0700"
+
+// ScheduleAt sets the time after which the notification is sent. A zero t
+// sends it right away.
+func (n *NotificationRequest) ScheduleAt(t time.Time) {
+	if t.IsZero() {
+		n.SendAfter = ""
+		return
+	}
+	n.SendAfter = t.Format(sendAfterLayout)
+}
+
+// DeliverInUserTimezone delivers the notification at hour:minute in the
+// timezone of each user. hour goes from 0 to 23.
+func (n *NotificationRequest) DeliverInUserTimezone(hour, minute int) error {
+	if hour < 0 || hour > 23 || minute < 0 || minute > 59 {
+		return &FieldError{Field: "delivery_time_of_day", Message: fmt.Sprintf("invalid time %d:%02d", hour, minute)}
+	}
+
+	meridiem := "AM"
+	if hour >= 12 {
+		meridiem = "PM"
+	}
+	if hour = hour % 12; hour == 0 {
+		hour = 12
+	}
+
+	n.DelayedOption = DelayedOptionTimezone
+	n.DeliveryTimeOfDay = fmt.Sprintf("%d:%02d%s", hour, minute, meridiem)
+	return nil
+}
+
+// DeliverByIntelligentDelivery delivers the notification at the time of day
+// each user is the most likely to open it.
+func (n *NotificationRequest) DeliverByIntelligentDelivery() {
+	n.DelayedOption = DelayedOptionLastActive
+	n.DeliveryTimeOfDay = ""
+}
+
+// checkSchedule checks that DelayedOption and DeliveryTimeOfDay make sense
+// together. A nil request is not scheduled.
+func (n *NotificationRequest) checkSchedule() *FieldError {
+	if n == nil {
+		return nil
+	}
+	switch n.DelayedOption {
+	case "", DelayedOptionLastActive:
+		if n.DeliveryTimeOfDay != "" {
+			return &FieldError{Field: "delivery_time_of_day", Message: fmt.Sprintf("requires delayed_option %q", DelayedOptionTimezone)}
+		}
+	case DelayedOptionTimezone:
+		if n.DeliveryTimeOfDay == "" {
+			return &FieldError{Field: "delayed_option", Message: fmt.Sprintf("%q requires delivery_time_of_day", DelayedOptionTimezone)}
+		}
+	default:
+		return &FieldError{Field: "delayed_option", Message: fmt.Sprintf("unknown option %q", n.DelayedOption)}
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package onesignal
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNotificationRequest_ScheduleAt(t *testing.T) {
+	n := &NotificationRequest{}
+	n.ScheduleAt(time.Date(2015, 9, 24, 14, 0, 0, 0, time.FixedZone("PDT", -7*3600)))
+	if got, want := n.SendAfter, "2015-09-24 14:00:00 GMT-0700"; got != want {
+		t.Errorf("SendAfter: %v, want %v", got, want)
+	}
+
+	n.ScheduleAt(time.Time{})
+	if got, want := n.SendAfter, ""; got != want {
+		t.Errorf("SendAfter: %v, want %v", got, want)
+	}
+}
+
+func TestNotificationRequest_DeliverInUserTimezone(t *testing.T) {
+	tests := []struct {
+		hour, minute int
+		want         string
+	}{
+		{0, 0, "12:00AM"},
+		{9, 5, "9:05AM"},
+		{12, 30, "12:30PM"},
+		{21, 45, "9:45PM"},
+	}
+	for _, tt := range tests {
+		n := &NotificationRequest{}
+		if err := n.DeliverInUserTimezone(tt.hour, tt.minute); err != nil {
+			t.Errorf("DeliverInUserTimezone(%d, %d) returned an error: %v", tt.hour, tt.minute, err)
+			continue
+		}
+		if got, want := n.DelayedOption, DelayedOptionTimezone; got != want {
+			t.Errorf("DelayedOption: %v, want %v", got, want)
+		}
+		if got := n.DeliveryTimeOfDay; got != tt.want {
+			t.Errorf("DeliverInUserTimezone(%d, %d): DeliveryTimeOfDay = %v, want %v", tt.hour, tt.minute, got, tt.want)
+		}
+	}
+
+	for _, hm := range [][2]int{{24, 0}, {-1, 0}, {10, 60}} {
+		n := &NotificationRequest{}
+		if err := n.DeliverInUserTimezone(hm[0], hm[1]); err == nil {
+			t.Errorf("DeliverInUserTimezone(%d, %d) should return an error", hm[0], hm[1])
+		}
+	}
+}
+
+func TestNotificationRequest_DeliverByIntelligentDelivery(t *testing.T) {
+	n := &NotificationRequest{}
+	n.DeliverInUserTimezone(9, 0)
+	n.DeliverByIntelligentDelivery()
+
+	if got, want := n.DelayedOption, DelayedOptionLastActive; got != want {
+		t.Errorf("DelayedOption: %v, want %v", got, want)
+	}
+	if got, want := n.DeliveryTimeOfDay, ""; got != want {
+		t.Errorf("DeliveryTimeOfDay: %v, want %v", got, want)
+	}
+	if err := n.checkSchedule(); err != nil {
+		t.Errorf("checkSchedule returned an error: %v", err)
+	}
+}
+
+func TestNotificationRequest_checkSchedule(t *testing.T) {
+	tests := []struct {
+		name    string
+		n       *NotificationRequest
+		wantErr bool
+	}{
+		{"none", &NotificationRequest{}, false},
+		{"timezone", &NotificationRequest{DelayedOption: DelayedOptionTimezone, DeliveryTimeOfDay: "9:00AM"}, false},
+		{"time of day without option", &NotificationRequest{DeliveryTimeOfDay: "9:00AM"}, true},
+		{"time of day with last-active", &NotificationRequest{DelayedOption: DelayedOptionLastActive, DeliveryTimeOfDay: "9:00AM"}, true},
+		{"timezone without time of day", &NotificationRequest{DelayedOption: DelayedOptionTimezone}, true},
+		{"unknown option", &NotificationRequest{DelayedOption: "tomorrow"}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.n.checkSchedule(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: checkSchedule returned %v, want error: %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestNotification_JSON(t *testing.T) {
+	var n Notification
+	err := json.Unmarshal([]byte(`{"id": "notif-id", "queued_at": 1415914655, "send_after": null}`), &n)
+	if err != nil {
+		t.Fatalf("Unmarshal returned an error: %v", err)
+	}
+	if got, want := n.ID, "notif-id"; got != want {
+		t.Errorf("ID: %v, want %v", got, want)
+	}
+	if got, want := n.QueuedAt, time.Unix(1415914655, 0); !got.Equal(want) {
+		t.Errorf("QueuedAt: %v, want %v", got, want)
+	}
+	if !n.SendAfter.IsZero() {
+		t.Errorf("SendAfter: %v, want zero time", n.SendAfter)
+	}
+
+	b, err := json.Marshal(n)
+	if err != nil {
+		t.Fatalf("Marshal returned an error: %v", err)
+	}
+	for _, want := range []string{`"id":"notif-id"`, `"queued_at":1415914655`, `"send_after":0`} {
+		if !strings.Contains(string(b), want) {
+			t.Errorf("Marshal = %s, should contain %s", b, want)
+		}
+	}
+}
+
+func TestNotificationsService_Create_invalidSchedule(t *testing.T) {
+	setup()
+	defer teardown()
+
+	requestSent := false
+	mux.HandleFunc("/notifications", func(w http.ResponseWriter, r *http.Request) {
+		requestSent = true
+	})
+
+	_, _, err := client.Notifications.Create(&NotificationRequest{
+		AppID:             "id123",
+		DeliveryTimeOfDay: "9:00AM",
+	})
+	if err == nil || !strings.Contains(err.Error(), "delivery_time_of_day") {
+		t.Errorf("Create returned %v, want a delivery_time_of_day error", err)
+	}
+	if requestSent {
+		t.Errorf("Request should not have been sent")
+	}
+}
@@ -1,12 +1,14 @@
 package onesignal
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
 	"reflect"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/tbalthazar/onesignal-go/testhelper"
 )
@@ -17,8 +19,8 @@ var sampleNotification1 = &Notification{
 	Failed:     1,
 	Converted:  3,
 	Remaining:  0,
-	QueuedAt:   1415914655,
-	SendAfter:  1415914655,
+	QueuedAt:   time.Unix(1415914655, 0),
+	SendAfter:  time.Unix(1415914655, 0),
 	Canceled:   false,
 	URL:        "https://yourWebsiteToOpen.com",
 	Headings: map[string]string{
@@ -37,8 +39,8 @@ var sampleNotification2 = &Notification{
 	Failed:     2,
 	Converted:  0,
 	Remaining:  0,
-	QueuedAt:   1415915123,
-	SendAfter:  1415915123,
+	QueuedAt:   time.Unix(1415915123, 0),
+	SendAfter:  time.Unix(1415915123, 0),
 	Canceled:   false,
 	Data: map[string]interface{}{
 		"foo":  "bar",
@@ -206,6 +208,26 @@ func TestNotificationsService_Create(t *testing.T) {
 	}
 }
 
+func TestNotificationsService_CreateWithContext(t *testing.T) {
+	setup()
+	defer teardown()
+
+	notificationRequest := sampleNotificationRequest
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	done := make(chan struct{})
+	defer close(done)
+
+	mux.HandleFunc("/notifications", func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	})
+
+	_, _, err := client.Notifications.CreateWithContext(ctx, notificationRequest)
+	if err != context.DeadlineExceeded {
+		t.Errorf("CreateWithContext returned %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
 func TestNotificationsService_Create_returnsError(t *testing.T) {
 	setup()
 	defer teardown()
@@ -274,6 +296,14 @@ func TestNotificationsService_Create_invalidPlayerIds(t *testing.T) {
 	if !reflect.DeepEqual(createResp, want) {
 		t.Errorf("Errors: %v, want %v", createResp, want)
 	}
+
+	errResp, ok := createResp.Err().(*ErrorResponse)
+	if !ok {
+		t.Fatalf("Err() should return an ErrorResponse but returned %v", createResp.Err())
+	}
+	if got, want := len(errResp.InvalidPlayerIDs), 2; got != want {
+		t.Errorf("InvalidPlayerIDs: got %d ids, want %d", got, want)
+	}
 }
 
 func TestNotificationsService_Create_noSubscribedPlayers(t *testing.T) {
@@ -0,0 +1,158 @@
+package onesignal
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Validate checks n for the mistakes OneSignal would reject it for, such as
+// a missing app_id or contents, no recipients, several ways of targeting
+// recipients, or more buttons than supported. It returns a *ValidationError
+// listing every invalid field, or nil.
+//
+// NotificationsService.Create calls Validate before sending the request,
+// unless the client was created with WithRequestValidation(false).
+func (n *NotificationRequest) Validate() error {
+	if n == nil {
+		// validated as an empty request, missing its required fields
+		n = &NotificationRequest{}
+	}
+
+	var errs []*FieldError
+	add := func(fieldErr *FieldError) {
+		if fieldErr != nil {
+			errs = append(errs, fieldErr)
+		}
+	}
+
+	if n.AppID == "" && len(n.AppIDs) == 0 {
+		add(&FieldError{Field: "app_id", Message: "required"})
+	}
+	add(n.checkTargeting())
+	add(n.checkFilters())
+	add(n.checkContents())
+	add(n.checkButtons())
+	add(n.checkSchedule())
+
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+// checkTargeting checks that n targets its recipients in exactly one way:
+// by segments, by filters or by device.
+func (n *NotificationRequest) checkTargeting() *FieldError {
+	segments := len(n.IncludedSegments) > 0
+	filters := n.hasFilters()
+	device := n.deviceField()
+
+	switch {
+	case len(n.ExcludedSegments) > 0 && !segments:
+		return &FieldError{Field: "excluded_segments", Message: "requires included_segments"}
+	case !segments && !filters && device == "":
+		return &FieldError{Field: "included_segments", Message: "no recipients, set included_segments, filters or include_player_ids"}
+	case segments && device != "":
+		return &FieldError{Field: device, Message: "cannot be combined with included_segments"}
+	case filters && device != "":
+		return &FieldError{Field: device, Message: "cannot be combined with filters"}
+	case segments && filters:
+		return &FieldError{Field: "filters", Message: "cannot be combined with included_segments"}
+	}
+	return nil
+}
+
+// hasFilters reports whether n targets its recipients with filters or
+// tags. Empty filters or tags target nobody, but invalid Filters count, to
+// be reported by checkFilters.
+func (n *NotificationRequest) hasFilters() bool {
+	switch f := n.Filters.(type) {
+	case Filters:
+		if len(f.conditions) > 0 || f.err != nil {
+			return true
+		}
+	case *Filters:
+		if f != nil && (len(f.conditions) > 0 || f.err != nil) {
+			return true
+		}
+	default:
+		if notEmpty(f) {
+			return true
+		}
+	}
+	return notEmpty(n.Tags)
+}
+
+// notEmpty reports whether v, set to raw filters or tags, is neither nil
+// nor an empty slice or map.
+func notEmpty(v interface{}) bool {
+	if v == nil {
+		return false
+	}
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Slice, reflect.Map:
+		return rv.Len() > 0
+	case reflect.Ptr:
+		return !rv.IsNil()
+	}
+	return true
+}
+
+// deviceField returns the JSON name of the first list of devices n targets,
+// or an empty string.
+func (n *NotificationRequest) deviceField() string {
+	fields := []struct {
+		name string
+		ids  []string
+	}{
+		{"include_player_ids", n.IncludePlayerIDs},
+		{"include_ios_tokens", n.IncludeIOSTokens},
+		{"include_android_reg_ids", n.IncludeAndroidRegIDs},
+		{"include_wp_uris", n.IncludeWPURIs},
+		{"include_wp_wns_uris", n.IncludeWPWNSURIs},
+		{"include_amazon_reg_ids", n.IncludeAmazonRegIDs},
+		{"include_chrome_reg_ids", n.IncludeChromeRegIDs},
+		{"include_chrome_web_reg_ids", n.IncludeChromeWebRegIDs},
+	}
+	for _, f := range fields {
+		if len(f.ids) > 0 {
+			return f.name
+		}
+	}
+	return ""
+}
+
+// checkFilters reports the error of Filters built with the Filters type.
+func (n *NotificationRequest) checkFilters() *FieldError {
+	var err error
+	switch f := n.Filters.(type) {
+	case Filters:
+		err = f.Err()
+	case *Filters:
+		if f != nil {
+			err = f.Err()
+		}
+	}
+	if err != nil {
+		return &FieldError{Field: "filters", Message: strings.TrimPrefix(err.Error(), "onesignal: ")}
+	}
+	return nil
+}
+
+// checkContents checks that n has contents, in English at least, unless it
+// uses a template or is a background notification carrying data.
+func (n *NotificationRequest) checkContents() *FieldError {
+	switch {
+	case len(n.Contents) > 0 && n.Contents["en"] == "":
+		return &FieldError{Field: "contents", Message: `missing English ("en") content`}
+	case len(n.Headings) > 0 && n.Headings["en"] == "":
+		return &FieldError{Field: "headings", Message: `missing English ("en") heading`}
+	case len(n.Contents) > 0 || n.TemplateID != "":
+		return nil
+	case n.ContentAvailable && n.Data == nil:
+		return &FieldError{Field: "content_available", Message: "requires data when there are no contents"}
+	case !n.ContentAvailable && !n.AndroidBackgroundData && !n.AmazonBackgroundData:
+		return &FieldError{Field: "contents", Message: "required"}
+	}
+	return nil
+}
@@ -0,0 +1,256 @@
+package onesignal
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestNotificationRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		n          *NotificationRequest
+		wantFields []string
+	}{
+		{
+			name: "valid",
+			n:    sampleNotificationRequest,
+		},
+		{
+			name: "valid with segments",
+			n: &NotificationRequest{
+				AppID:            "id123",
+				Contents:         map[string]string{"en": "English message"},
+				IncludedSegments: []string{"All"},
+				ExcludedSegments: []string{"Inactive"},
+			},
+		},
+		{
+			name: "valid background notification",
+			n: &NotificationRequest{
+				AppID:            "id123",
+				ContentAvailable: true,
+				Data:             map[string]string{"foo": "bar"},
+				IncludePlayerIDs: []string{"playerid123"},
+			},
+		},
+		{
+			name: "valid template",
+			n: &NotificationRequest{
+				AppID:            "id123",
+				TemplateID:       "template123",
+				IncludedSegments: []string{"All"},
+			},
+		},
+		{
+			name:       "empty",
+			n:          &NotificationRequest{},
+			wantFields: []string{"app_id", "included_segments", "contents"},
+		},
+		{
+			name:       "nil",
+			n:          nil,
+			wantFields: []string{"app_id", "included_segments", "contents"},
+		},
+		{
+			name: "empty filters and tags",
+			n: &NotificationRequest{
+				AppID:    "id123",
+				Contents: map[string]string{"en": "English message"},
+				Filters:  Filters{},
+				Tags:     []map[string]string{},
+			},
+			wantFields: []string{"included_segments"},
+		},
+		{
+			name: "empty raw filters",
+			n: &NotificationRequest{
+				AppID:    "id123",
+				Contents: map[string]string{"en": "English message"},
+				Filters:  []map[string]string{},
+			},
+			wantFields: []string{"included_segments"},
+		},
+		{
+			name: "raw filters",
+			n: &NotificationRequest{
+				AppID:    "id123",
+				Contents: map[string]string{"en": "English message"},
+				Filters:  []map[string]string{{"field": "country", "relation": "=", "value": "FR"}},
+			},
+		},
+		{
+			name: "segments and player ids",
+			n: &NotificationRequest{
+				AppID:            "id123",
+				Contents:         map[string]string{"en": "English message"},
+				IncludedSegments: []string{"All"},
+				IncludePlayerIDs: []string{"playerid123"},
+			},
+			wantFields: []string{"include_player_ids"},
+		},
+		{
+			name: "filters and tokens",
+			n: &NotificationRequest{
+				AppID:            "id123",
+				Contents:         map[string]string{"en": "English message"},
+				Filters:          Country("FR"),
+				IncludeIOSTokens: []string{"token"},
+			},
+			wantFields: []string{"include_ios_tokens"},
+		},
+		{
+			name: "segments and filters",
+			n: &NotificationRequest{
+				AppID:            "id123",
+				Contents:         map[string]string{"en": "English message"},
+				IncludedSegments: []string{"All"},
+				Filters:          Country("FR"),
+			},
+			wantFields: []string{"filters"},
+		},
+		{
+			name: "excluded segments only",
+			n: &NotificationRequest{
+				AppID:            "id123",
+				Contents:         map[string]string{"en": "English message"},
+				ExcludedSegments: []string{"Inactive"},
+			},
+			wantFields: []string{"excluded_segments"},
+		},
+		{
+			name: "invalid filters",
+			n: &NotificationRequest{
+				AppID:    "id123",
+				Contents: map[string]string{"en": "English message"},
+				Filters:  Language().GreaterThan("fr"),
+			},
+			wantFields: []string{"filters"},
+		},
+		{
+			name: "missing english",
+			n: &NotificationRequest{
+				AppID:            "id123",
+				Contents:         map[string]string{"fr": "Message en français"},
+				Headings:         map[string]string{"fr": "Titre"},
+				IncludedSegments: []string{"All"},
+			},
+			wantFields: []string{"contents"},
+		},
+		{
+			name: "missing english heading",
+			n: &NotificationRequest{
+				AppID:            "id123",
+				Contents:         map[string]string{"en": "English message"},
+				Headings:         map[string]string{"fr": "Titre"},
+				IncludedSegments: []string{"All"},
+			},
+			wantFields: []string{"headings"},
+		},
+		{
+			name: "content available without contents",
+			n: &NotificationRequest{
+				AppID:            "id123",
+				ContentAvailable: true,
+				IncludedSegments: []string{"All"},
+			},
+			wantFields: []string{"content_available"},
+		},
+		{
+			name: "buttons and schedule",
+			n: &NotificationRequest{
+				AppID:             "id123",
+				Contents:          map[string]string{"en": "English message"},
+				IncludedSegments:  []string{"All"},
+				Buttons:           []Button{{ID: "1"}, {ID: "2"}, {ID: "3"}, {ID: "4"}},
+				DeliveryTimeOfDay: "9:00AM",
+			},
+			wantFields: []string{"buttons", "delivery_time_of_day"},
+		},
+	}
+
+	for _, tt := range tests {
+		err := tt.n.Validate()
+		if tt.wantFields == nil {
+			if err != nil {
+				t.Errorf("%s: Validate returned an error: %v", tt.name, err)
+			}
+			continue
+		}
+
+		validationErr, ok := err.(*ValidationError)
+		if !ok {
+			t.Errorf("%s: Validate returned %v, want a *ValidationError", tt.name, err)
+			continue
+		}
+		var fields []string
+		for _, fieldErr := range validationErr.Errors {
+			fields = append(fields, fieldErr.Field)
+		}
+		if !reflect.DeepEqual(fields, tt.wantFields) {
+			t.Errorf("%s: invalid fields: %v, want %v", tt.name, fields, tt.wantFields)
+		}
+	}
+}
+
+func TestValidationError(t *testing.T) {
+	err := &ValidationError{Errors: []*FieldError{
+		{Field: "app_id", Message: "required"},
+		{Field: "contents", Message: "required"},
+	}}
+
+	if got, want := err.Error(), "onesignal: invalid request: app_id: required; contents: required"; got != want {
+		t.Errorf("Error: %q, want %q", got, want)
+	}
+
+	var fieldErr *FieldError
+	if !errors.As(fmt.Errorf("wrapped: %w", err), &fieldErr) {
+		t.Fatalf("errors.As should find a *FieldError")
+	}
+	if got, want := fieldErr.Field, "app_id"; got != want {
+		t.Errorf("Field: %v, want %v", got, want)
+	}
+}
+
+func TestNotificationsService_Create_invalid(t *testing.T) {
+	setup()
+	defer teardown()
+
+	requestSent := false
+	mux.HandleFunc("/notifications", func(w http.ResponseWriter, r *http.Request) {
+		requestSent = true
+	})
+
+	_, resp, err := client.Notifications.Create(&NotificationRequest{})
+	if _, ok := err.(*ValidationError); !ok {
+		t.Errorf("Create returned %v, want a *ValidationError", err)
+	}
+	if resp != nil {
+		t.Errorf("Response should be nil")
+	}
+	if requestSent {
+		t.Errorf("Request should not have been sent")
+	}
+}
+
+func TestNotificationsService_Create_validationDisabled(t *testing.T) {
+	setup()
+	defer teardown()
+	WithRequestValidation(false)(client)
+
+	requestSent := false
+	mux.HandleFunc("/notifications", func(w http.ResponseWriter, r *http.Request) {
+		requestSent = true
+		fmt.Fprint(w, `{"id": "notif-fake-id", "recipients": 0}`)
+	})
+
+	_, _, err := client.Notifications.Create(&NotificationRequest{AppID: "id123"})
+	if err != nil {
+		t.Errorf("Create returned an error: %v", err)
+	}
+	if !requestSent {
+		t.Errorf("Request has not been sent")
+	}
+}
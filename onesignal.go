@@ -3,16 +3,18 @@ package onesignal
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io"
-	"log"
+	"io/ioutil"
+	"log/slog"
 	"net/http"
 	"net/url"
-	"strings"
 )
 
 const (
-	defaultBaseURL = "https://onesignal.com/api/v1/"
+	defaultBaseURL   = "https://onesignal.com/api/v1/"
+	defaultUserAgent = "onesignal-go"
 )
 
 // AuthKeyType specifies the token used to authentify the requests
@@ -30,9 +32,30 @@ type Client struct {
 	UserKey string
 	Client  *http.Client
 
+	// UserAgent is sent in the User-Agent header of every request.
+	UserAgent string
+
+	// RetryPolicy controls how failed requests are retried. If nil, every
+	// request is attempted only once.
+	RetryPolicy *RetryPolicy
+
+	// RateLimiters throttle outgoing requests according to the AuthKeyType
+	// they are authorized with. There is no client-side limit for a key
+	// type that has no RateLimiter.
+	RateLimiters map[AuthKeyType]*RateLimiter
+
 	Apps          *AppsService
 	Players       *PlayersService
 	Notifications *NotificationsService
+	Templates     *TemplatesService
+	Segments      *SegmentsService
+	Outcomes      *OutcomesService
+
+	middlewares []Middleware
+	logger      *slog.Logger
+	logBodies   bool
+
+	skipValidation bool
 }
 
 // SuccessResponse  wraps the standard http.Response for several API methods
@@ -41,37 +64,34 @@ type SuccessResponse struct {
 	Success bool `json:"success"`
 }
 
-// ErrorResponse reports one or more errors caused by an API request.
-type ErrorResponse struct {
-	Messages []string `json:"errors"`
-}
-
-func (e *ErrorResponse) Error() string {
-	msg := "OneSignal returned those error messages:\n - "
-	return msg + strings.Join(e.Messages, "\n - ")
-}
-
-// NewClient returns a new OneSignal API client.
-func NewClient(httpClient *http.Client) *Client {
-	if httpClient == nil {
-		httpClient = http.DefaultClient
-	}
-
+// NewClient returns a new OneSignal API client configured with opts. An
+// error is returned if any of the options is invalid.
+func NewClient(opts ...Option) (*Client, error) {
 	baseURL, err := url.Parse(defaultBaseURL)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
 	c := &Client{
-		BaseURL: baseURL,
-		Client:  httpClient,
+		BaseURL:   baseURL,
+		Client:    http.DefaultClient,
+		UserAgent: defaultUserAgent,
+	}
+
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
 	}
 
 	c.Apps = &AppsService{client: c}
 	c.Players = &PlayersService{client: c}
 	c.Notifications = &NotificationsService{client: c}
+	c.Templates = &TemplatesService{client: c}
+	c.Segments = &SegmentsService{client: c}
+	c.Outcomes = &OutcomesService{client: c}
 
-	return c
+	return c, nil
 }
 
 // NewRequest creates an API request. path is a relative URL, like "/apps". The
@@ -79,6 +99,12 @@ func NewClient(httpClient *http.Client) *Client {
 // The AuthKeyType will determine which authorization token (APP or USER) is
 // used for the request.
 func (c *Client) NewRequest(method, path string, body interface{}, authKeyType AuthKeyType) (*http.Request, error) {
+	return c.NewRequestWithContext(context.Background(), method, path, body, authKeyType)
+}
+
+// NewRequestWithContext is like NewRequest but the returned request carries
+// ctx, so that sending it and reading its response can be canceled.
+func (c *Client) NewRequestWithContext(ctx context.Context, method, path string, body interface{}, authKeyType AuthKeyType) (*http.Request, error) {
 	// build the URL
 	u, err := url.Parse(c.BaseURL.String() + path)
 	if err != nil {
@@ -94,11 +120,11 @@ func (c *Client) NewRequest(method, path string, body interface{}, authKeyType A
 			return nil, err
 		}
 		buf = b
-		// log.Println("Body is: " + b.String())
 	}
 
-	// create the request
-	req, err := http.NewRequest(method, u.String(), buf)
+	// create the request, remembering which key authorizes it
+	ctx = context.WithValue(ctx, authKeyTypeContextKey{}, authKeyType)
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), buf)
 	if err != nil {
 		return nil, err
 	}
@@ -106,13 +132,14 @@ func (c *Client) NewRequest(method, path string, body interface{}, authKeyType A
 	// headers
 	req.Header.Add("Content-Type", "application/json")
 	req.Header.Add("Accept", "application/json")
+	if c.UserAgent != "" {
+		req.Header.Add("User-Agent", c.UserAgent)
+	}
 
 	if authKeyType == APP {
 		req.Header.Add("Authorization", "Basic "+c.AppKey)
-		// log.Println("Authorization header is AppKey")
 	} else {
 		req.Header.Add("Authorization", "Basic "+c.UserKey)
-		// log.Println("Authorization header is UserKey")
 	}
 
 	return req, nil
@@ -120,11 +147,18 @@ func (c *Client) NewRequest(method, path string, body interface{}, authKeyType A
 
 // Do sends an API request and returns the API response. The API response is
 // JSON decoded and stored in the value pointed to by v, or returned as an
-// error if an API error has occurred.
+// error if an API error has occurred. If the request's context is canceled
+// while sending or while decoding the response, the context's error is
+// returned.
 func (c *Client) Do(r *http.Request, v interface{}) (*http.Response, error) {
+	ctx := r.Context()
+
 	// send the request
-	resp, err := c.Client.Do(r)
+	resp, err := c.doer().Do(r)
 	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
 		return nil, err
 	}
 	defer resp.Body.Close()
@@ -134,14 +168,12 @@ func (c *Client) Do(r *http.Request, v interface{}) (*http.Response, error) {
 		return resp, err
 	}
 
-	// // log body for debug
-	// b := new(bytes.Buffer)
-	// b.ReadFrom(resp.Body)
-	// log.Println("response body: ", b.String())
-
 	dec := json.NewDecoder(resp.Body)
 	err = dec.Decode(&v)
 	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return resp, ctxErr
+		}
 		return resp, err
 	}
 	return resp, nil
@@ -151,22 +183,27 @@ func (c *Client) Do(r *http.Request, v interface{}) (*http.Response, error) {
 // present.  A response is considered an error if it has a status code outside
 // the 200 range.  API error responses are expected to have either no response
 // body, or a JSON response body that maps to ErrorResponse.  Any other
-// response body will be silently ignored.
+// response body is kept in ErrorResponse.Body.  A 429 Too Many Requests
+// response is reported as a *RateLimitError.
 func CheckResponse(r *http.Response) error {
-	switch r.StatusCode {
-	case http.StatusOK:
+	if r.StatusCode == http.StatusOK {
 		return nil
-	case http.StatusInternalServerError:
-		return &ErrorResponse{
-			Messages: []string{"Internal Server Error"},
-		}
-	default:
-		var errResp ErrorResponse
-		dec := json.NewDecoder(r.Body)
-		err := dec.Decode(&errResp)
-		if err != nil {
-			errResp.Messages = []string{"Couldn't decode response body JSON"}
-		}
-		return &errResp
 	}
+
+	errResp := &ErrorResponse{
+		Response:   r,
+		StatusCode: r.StatusCode,
+		RequestID:  r.Header.Get("X-Request-Id"),
+	}
+	if r.Body != nil {
+		errResp.Body, _ = ioutil.ReadAll(r.Body)
+	}
+	if err := errResp.decode(errResp.Body); err != nil {
+		errResp.Messages = []string{"Couldn't decode response body JSON"}
+	}
+
+	if r.StatusCode == http.StatusTooManyRequests {
+		return newRateLimitError(r, errResp)
+	}
+	return errResp
 }
@@ -1,12 +1,12 @@
 package onesignal
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
-	"net/url"
 	"reflect"
 	"strings"
 	"testing"
@@ -24,11 +24,11 @@ func setup() {
 	server = httptest.NewServer(mux)
 
 	// create a client, giving it the test server URL
-	client = NewClient(nil)
-	client.AppKey = "fake-app-key"
-	client.UserKey = "fake-user-key"
-	url, _ := url.Parse(server.URL)
-	client.BaseURL = url
+	client, _ = NewClient(
+		WithAppKey("fake-app-key"),
+		WithUserKey("fake-user-key"),
+		WithBaseURL(server.URL),
+	)
 }
 
 func teardown() {
@@ -55,7 +55,7 @@ func testBody(t *testing.T, r *http.Request, body interface{}, want interface{})
 }
 
 func TestNewClient(t *testing.T) {
-	c := NewClient(nil)
+	c, _ := NewClient()
 
 	if got, want := c.BaseURL.String(), defaultBaseURL; got != want {
 		t.Errorf("NewClient BaseURL is %v, want %v", got, want)
@@ -76,12 +76,24 @@ func TestNewClient(t *testing.T) {
 	if got, want := c.Notifications.client, c; got != want {
 		t.Errorf("NewClient.NotificationsService.client is %v, want %v", got, want)
 	}
+
+	if got, want := c.Templates.client, c; got != want {
+		t.Errorf("NewClient.TemplatesService.client is %v, want %v", got, want)
+	}
+
+	if got, want := c.Segments.client, c; got != want {
+		t.Errorf("NewClient.SegmentsService.client is %v, want %v", got, want)
+	}
+
+	if got, want := c.Outcomes.client, c; got != want {
+		t.Errorf("NewClient.OutcomesService.client is %v, want %v", got, want)
+	}
 }
 
 func TestNewClient_withCustomHTTPClient(t *testing.T) {
 	httpClient := &http.Client{}
 
-	c := NewClient(httpClient)
+	c, _ := NewClient(WithHTTPClient(httpClient))
 
 	if got, want := c.Client, httpClient; got != want {
 		t.Errorf("NewClient Client is %v, want %v", got, want)
@@ -91,7 +103,7 @@ func TestNewClient_withCustomHTTPClient(t *testing.T) {
 func TestNewRequest(t *testing.T) {
 	appKey := "fake app key"
 	userKey := "fake user key"
-	c := NewClient(nil)
+	c, _ := NewClient()
 	c.AppKey = appKey
 	c.UserKey = userKey
 
@@ -126,7 +138,7 @@ func TestNewRequest(t *testing.T) {
 func TestNewRequest_userKeyType(t *testing.T) {
 	appKey := "fake app key"
 	userKey := "fake user key"
-	c := NewClient(nil)
+	c, _ := NewClient()
 	c.AppKey = appKey
 	c.UserKey = userKey
 
@@ -136,10 +148,10 @@ func TestNewRequest_userKeyType(t *testing.T) {
 }
 
 func TestNewRequest_invalidJSON(t *testing.T) {
-	c := NewClient(nil)
+	c, _ := NewClient()
 
 	type T struct {
-		A map[int]interface{}
+		A chan int
 	}
 	_, err := c.NewRequest("GET", "/", &T{}, APP)
 
@@ -151,8 +163,23 @@ func TestNewRequest_invalidJSON(t *testing.T) {
 	}
 }
 
+func TestNewRequestWithContext(t *testing.T) {
+	c, _ := NewClient()
+
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "value")
+	req, err := c.NewRequestWithContext(ctx, "GET", "foo", nil, APP)
+	if err != nil {
+		t.Fatalf("NewRequestWithContext returned unexpected error: %v", err)
+	}
+
+	if got := req.Context().Value(key{}); got != "value" {
+		t.Errorf("NewRequestWithContext context value is %v, want %v", got, "value")
+	}
+}
+
 func TestNewRequest_emptyBody(t *testing.T) {
-	c := NewClient(nil)
+	c, _ := NewClient()
 
 	req, err := c.NewRequest("GET", "/", nil, APP)
 
@@ -204,6 +231,48 @@ func TestDo_httpError(t *testing.T) {
 	}
 }
 
+func TestDo_canceledContext(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"A":"a"}`)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, _ := client.NewRequestWithContext(ctx, "GET", "/", nil, APP)
+	_, err := client.Do(req, nil)
+
+	if err != context.Canceled {
+		t.Errorf("Do returned %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestDo_contextCanceledWhileDecoding(t *testing.T) {
+	setup()
+	defer teardown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	defer close(done)
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		// send the beginning of the body, then stall until the client gives up
+		fmt.Fprint(w, `{"A":`)
+		w.(http.Flusher).Flush()
+		cancel()
+		<-done
+	})
+
+	req, _ := client.NewRequestWithContext(ctx, "GET", "/", nil, APP)
+	_, err := client.Do(req, new(struct{ A string }))
+
+	if err != context.Canceled {
+		t.Errorf("Do returned %v, want %v", err, context.Canceled)
+	}
+}
+
 func TestCheckResponse_ok(t *testing.T) {
 	r := &http.Response{
 		StatusCode: http.StatusOK,
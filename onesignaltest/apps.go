@@ -0,0 +1,136 @@
+package onesignaltest
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/tbalthazar/onesignal-go"
+)
+
+// AddApp adds app to s, as if it had been created with the API, and returns
+// its ID. An ID is generated if app has none.
+func (s *Server) AddApp(app onesignal.App) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addApp(app)
+}
+
+func (s *Server) addApp(app onesignal.App) string {
+	if app.ID == "" {
+		app.ID = s.newID()
+	}
+	now := time.Now().UTC()
+	if app.CreatedAt.IsZero() {
+		app.CreatedAt = now
+	}
+	if app.UpdatedAt.IsZero() {
+		app.UpdatedAt = now
+	}
+	if app.BasicAuthKey == "" {
+		app.BasicAuthKey = s.AppKey
+	}
+	s.apps = append(s.apps, &app)
+	return app.ID
+}
+
+// Apps returns the apps of s, in the order they were created.
+func (s *Server) Apps() []onesignal.App {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	apps := make([]onesignal.App, len(s.apps))
+	for i, app := range s.apps {
+		apps[i] = s.withCounts(app)
+	}
+	return apps
+}
+
+// app returns the app with this ID, or nil. s must be locked.
+func (s *Server) app(id string) *onesignal.App {
+	for _, app := range s.apps {
+		if app.ID == id {
+			return app
+		}
+	}
+	return nil
+}
+
+// withCounts returns a copy of app with its player counts. s must be locked.
+func (s *Server) withCounts(app *onesignal.App) onesignal.App {
+	a := *app
+	for _, p := range s.players {
+		if p.appID == app.ID {
+			a.Players++
+			if !p.InvalidIdentifier {
+				a.MessagablePlayers++
+			}
+		}
+	}
+	return a
+}
+
+func (s *Server) listApps(w http.ResponseWriter, r *http.Request) {
+	apps := make([]onesignal.App, len(s.apps))
+	for i, app := range s.apps {
+		apps[i] = s.withCounts(app)
+	}
+	writeJSON(w, http.StatusOK, apps)
+}
+
+func (s *Server) getApp(w http.ResponseWriter, r *http.Request) {
+	app := s.app(r.PathValue("id"))
+	if app == nil {
+		writeErrors(w, http.StatusNotFound, "App not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.withCounts(app))
+}
+
+func (s *Server) createApp(w http.ResponseWriter, r *http.Request) {
+	var app onesignal.App
+	if !decodeBody(w, r, &app) {
+		return
+	}
+	if app.Name == "" {
+		writeErrors(w, http.StatusBadRequest, "Name can't be blank")
+		return
+	}
+
+	// the server decides of the ID, the dates and the key
+	app = onesignal.App{
+		Name:                             app.Name,
+		GCMKey:                           app.GCMKey,
+		ChromeKey:                        app.ChromeKey,
+		ChromeWebOrigin:                  app.ChromeWebOrigin,
+		ChromeWebGCMSenderID:             app.ChromeWebGCMSenderID,
+		ChromeWebDefaultNotificationIcon: app.ChromeWebDefaultNotificationIcon,
+		ChromeWebSubDomain:               app.ChromeWebSubDomain,
+		APNSEnv:                          app.APNSEnv,
+		SafariSiteOrigin:                 app.SafariSiteOrigin,
+		SafariIcon1616:                   app.SafariIcon1616,
+		SafariIcon3232:                   app.SafariIcon3232,
+		SafariIcon6464:                   app.SafariIcon6464,
+		SafariIcon128128:                 app.SafariIcon128128,
+		SafariIcon256256:                 app.SafariIcon256256,
+		SiteName:                         app.SiteName,
+	}
+	id := s.addApp(app)
+	writeJSON(w, http.StatusOK, s.withCounts(s.app(id)))
+}
+
+func (s *Server) updateApp(w http.ResponseWriter, r *http.Request) {
+	app := s.app(r.PathValue("id"))
+	if app == nil {
+		writeErrors(w, http.StatusNotFound, "App not found")
+		return
+	}
+
+	// the fields set in the body overwrite the ones of app
+	updated := *app
+	if !decodeBody(w, r, &updated) {
+		return
+	}
+	updated.ID, updated.CreatedAt, updated.BasicAuthKey = app.ID, app.CreatedAt, app.BasicAuthKey
+	updated.UpdatedAt = time.Now().UTC()
+	*app = updated
+	writeJSON(w, http.StatusOK, s.withCounts(app))
+}
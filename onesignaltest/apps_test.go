@@ -0,0 +1,63 @@
+package onesignaltest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/tbalthazar/onesignal-go"
+)
+
+func TestServer_apps(t *testing.T) {
+	srv, client, appID := setup(t)
+
+	app, _, err := client.Apps.Create(&onesignal.AppRequest{Name: "Other app", SiteName: "example"})
+	if err != nil {
+		t.Fatalf("Apps.Create returned an error: %v", err)
+	}
+	if app.ID == "" || app.ID == appID {
+		t.Errorf("Apps.Create returned the ID %q", app.ID)
+	}
+	if got, want := app.BasicAuthKey, srv.AppKey; got != want {
+		t.Errorf("BasicAuthKey is %v, want %v", got, want)
+	}
+
+	updated, _, err := client.Apps.Update(app.ID, &onesignal.AppRequest{Name: "Renamed app"})
+	if err != nil {
+		t.Fatalf("Apps.Update returned an error: %v", err)
+	}
+	if got, want := updated.Name, "Renamed app"; got != want {
+		t.Errorf("Name is %v, want %v", got, want)
+	}
+	if got, want := updated.SiteName, "example"; got != want {
+		t.Errorf("SiteName is %v, want %v", got, want)
+	}
+
+	srv.AddPlayer(app.ID, onesignal.Player{})
+	got, _, err := client.Apps.Get(app.ID)
+	if err != nil {
+		t.Fatalf("Apps.Get returned an error: %v", err)
+	}
+	if got.Name != "Renamed app" || got.Players != 1 || got.MessagablePlayers != 1 {
+		t.Errorf("Apps.Get returned %+v", got)
+	}
+
+	apps, _, err := client.Apps.List()
+	if err != nil {
+		t.Fatalf("Apps.List returned an error: %v", err)
+	}
+	if len(apps) != 2 || apps[0].ID != appID || apps[1].ID != app.ID {
+		t.Errorf("Apps.List returned %+v", apps)
+	}
+}
+
+func TestServer_apps_errors(t *testing.T) {
+	_, client, _ := setup(t)
+
+	if _, _, err := client.Apps.Get("unknown"); !errors.Is(err, onesignal.ErrNotFound) {
+		t.Errorf("Apps.Get returned %v, want %v", err, onesignal.ErrNotFound)
+	}
+	_, _, err := client.Apps.Create(&onesignal.AppRequest{})
+	if got, want := onesignal.ErrorCategory(err), "client"; got != want {
+		t.Errorf("Apps.Create returned %v, want a %v error", err, want)
+	}
+}
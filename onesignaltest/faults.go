@@ -0,0 +1,104 @@
+package onesignaltest
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A Fault alters the responses of a Server, to test how a client copes with
+// a slow or failing API.
+type Fault struct {
+	// Method and Path restrict the fault to the requests with this method
+	// and whose path starts with Path, such as "/notifications". Empty
+	// values match every request.
+	Method string
+	Path   string
+
+	// Latency delays the response, or the error if StatusCode is set. The
+	// delay is cut short if the client gives up on the request.
+	Latency time.Duration
+
+	// StatusCode, if not zero, is replied with a OneSignal error body
+	// instead of handling the request, such as http.StatusTooManyRequests
+	// or http.StatusInternalServerError.
+	StatusCode int
+
+	// RetryAfter, if not zero, sets the Retry-After header of the error
+	// response, rounded up to the second.
+	RetryAfter time.Duration
+
+	// Times is the number of requests the fault applies to, after which it
+	// is removed. Zero means every request.
+	Times int
+}
+
+func (f *Fault) matches(r *http.Request) bool {
+	return (f.Method == "" || f.Method == r.Method) && strings.HasPrefix(r.URL.Path, f.Path)
+}
+
+// InjectFault adds a fault to s. When several faults match a request, the
+// first one injected applies.
+func (s *Server) InjectFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes the faults injected in s.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// fault counts r and returns the fault to apply to it, if any.
+func (s *Server) fault(r *http.Request) *Fault {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+	for i, f := range s.faults {
+		if !f.matches(r) {
+			continue
+		}
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+			}
+		}
+		return f
+	}
+	return nil
+}
+
+// intercept applies the faults of s to the requests handled by next.
+func (s *Server) intercept(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f := s.fault(r)
+		if f == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if f.Latency > 0 {
+			timer := time.NewTimer(f.Latency)
+			select {
+			case <-timer.C:
+			case <-r.Context().Done():
+				timer.Stop()
+				return
+			}
+		}
+		if f.StatusCode == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if f.RetryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(f.RetryAfter.Seconds()))))
+		}
+		writeErrors(w, f.StatusCode, http.StatusText(f.StatusCode))
+	})
+}
@@ -0,0 +1,80 @@
+package onesignaltest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/tbalthazar/onesignal-go"
+)
+
+func TestServer_InjectFault_status(t *testing.T) {
+	srv, client, appID := setup(t)
+
+	srv.InjectFault(Fault{Method: "GET", Path: "/players", StatusCode: http.StatusInternalServerError, Times: 1})
+
+	opt := &onesignal.PlayerListOptions{AppID: appID}
+	if _, _, err := client.Players.List(opt); !errors.Is(err, onesignal.ErrServer) {
+		t.Errorf("Players.List returned %v, want %v", err, onesignal.ErrServer)
+	}
+	// the fault only applied once, and not to other paths
+	if _, _, err := client.Players.List(opt); err != nil {
+		t.Errorf("Players.List returned an error: %v", err)
+	}
+	srv.InjectFault(Fault{Path: "/notifications", StatusCode: http.StatusInternalServerError})
+	if _, _, err := client.Players.List(opt); err != nil {
+		t.Errorf("Players.List returned an error: %v", err)
+	}
+
+	srv.ClearFaults()
+	if _, _, err := client.Notifications.List(&onesignal.NotificationListOptions{AppID: appID}); err != nil {
+		t.Errorf("Notifications.List returned an error: %v", err)
+	}
+}
+
+func TestServer_InjectFault_rateLimited(t *testing.T) {
+	srv, client, appID := setup(t)
+
+	srv.InjectFault(Fault{Path: "/players", StatusCode: http.StatusTooManyRequests, RetryAfter: 1500 * time.Millisecond})
+
+	_, _, err := client.Players.List(&onesignal.PlayerListOptions{AppID: appID})
+	var rateLimitErr *onesignal.RateLimitError
+	if !errors.As(err, &rateLimitErr) {
+		t.Fatalf("Players.List returned %v, want a *RateLimitError", err)
+	}
+	if got, want := rateLimitErr.RetryAfter, 2*time.Second; got != want {
+		t.Errorf("RetryAfter is %v, want %v", got, want)
+	}
+}
+
+func TestServer_InjectFault_retried(t *testing.T) {
+	srv, _, appID := setup(t)
+
+	client, err := srv.Client(onesignal.WithRetryPolicy(&onesignal.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}))
+	if err != nil {
+		t.Fatalf("Client returned an error: %v", err)
+	}
+	srv.InjectFault(Fault{StatusCode: http.StatusServiceUnavailable, Times: 2})
+
+	if _, _, err := client.Players.List(&onesignal.PlayerListOptions{AppID: appID}); err != nil {
+		t.Errorf("Players.List returned an error: %v", err)
+	}
+	if got, want := srv.Requests(), 3; got != want {
+		t.Errorf("Requests returned %d, want %d", got, want)
+	}
+}
+
+func TestServer_InjectFault_latency(t *testing.T) {
+	srv, client, appID := setup(t)
+
+	srv.InjectFault(Fault{Latency: time.Hour})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, _, err := client.Players.ListWithContext(ctx, &onesignal.PlayerListOptions{AppID: appID})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Players.List returned %v, want %v", err, context.DeadlineExceeded)
+	}
+}
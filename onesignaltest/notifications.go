@@ -0,0 +1,187 @@
+package onesignaltest
+
+import (
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/tbalthazar/onesignal-go"
+)
+
+// maxNotificationsLimit is the maximum number of notifications listed per
+// page.
+const maxNotificationsLimit = 50
+
+// notification is a onesignal.Notification along with the app it belongs to
+// and the request that created it.
+type notification struct {
+	appID   string
+	request onesignal.NotificationRequest
+	onesignal.Notification
+}
+
+// Notifications returns the notifications of the app appID, the most recent
+// first.
+func (s *Server) Notifications(appID string) []onesignal.Notification {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var notifications []onesignal.Notification
+	for _, n := range s.appNotifications(appID) {
+		notifications = append(notifications, n.Notification)
+	}
+	return notifications
+}
+
+// NotificationRequest returns the request that created the notification
+// with this ID, so that tests can check everything a client sent.
+func (s *Server) NotificationRequest(notificationID string) (onesignal.NotificationRequest, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, n := range s.notifications {
+		if n.ID == notificationID {
+			return n.request, true
+		}
+	}
+	return onesignal.NotificationRequest{}, false
+}
+
+// appNotifications returns the notifications of the app appID, the most
+// recent first. s must be locked.
+func (s *Server) appNotifications(appID string) []*notification {
+	var notifications []*notification
+	for _, n := range slices.Backward(s.notifications) {
+		if n.appID == appID {
+			notifications = append(notifications, n)
+		}
+	}
+	return notifications
+}
+
+// notification returns the notification of the app appID with this ID, or
+// nil. s must be locked.
+func (s *Server) notification(appID, id string) *notification {
+	for _, n := range s.notifications {
+		if n.appID == appID && n.ID == id {
+			return n
+		}
+	}
+	return nil
+}
+
+func (s *Server) listNotifications(w http.ResponseWriter, r *http.Request) {
+	appID := r.URL.Query().Get("app_id")
+	if s.app(appID) == nil {
+		writeErrors(w, http.StatusBadRequest, "app_id not found")
+		return
+	}
+
+	all := s.appNotifications(appID)
+	start, end, limit := paginate(r, len(all), maxNotificationsLimit)
+	res := &onesignal.NotificationListResponse{
+		TotalCount:    len(all),
+		Offset:        start,
+		Limit:         limit,
+		Notifications: []onesignal.Notification{},
+	}
+	for _, n := range all[start:end] {
+		res.Notifications = append(res.Notifications, n.Notification)
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) getNotification(w http.ResponseWriter, r *http.Request) {
+	n := s.notification(r.URL.Query().Get("app_id"), r.PathValue("id"))
+	if n == nil {
+		writeErrors(w, http.StatusNotFound, "Could not find notification with id: "+r.PathValue("id"))
+		return
+	}
+	writeJSON(w, http.StatusOK, n.Notification)
+}
+
+// createNotification sends a notification to the players it targets. The
+// segments and filters are not evaluated: a notification sent to segments
+// or filters reaches every player of the app.
+func (s *Server) createNotification(w http.ResponseWriter, r *http.Request) {
+	var req onesignal.NotificationRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if s.app(req.AppID) == nil {
+		writeErrors(w, http.StatusBadRequest, "app_id not found")
+		return
+	}
+
+	// count the recipients, rejecting the unknown player IDs
+	recipients := len(req.IncludeIOSTokens) + len(req.IncludeAndroidRegIDs) +
+		len(req.IncludeWPURIs) + len(req.IncludeWPWNSURIs) + len(req.IncludeAmazonRegIDs) +
+		len(req.IncludeChromeRegIDs) + len(req.IncludeChromeWebRegIDs)
+	var invalidPlayerIDs []string
+	for _, id := range req.IncludePlayerIDs {
+		if p := s.player(id); p != nil && p.appID == req.AppID {
+			recipients++
+		} else {
+			invalidPlayerIDs = append(invalidPlayerIDs, id)
+		}
+	}
+	if len(req.IncludedSegments) > 0 || req.Filters != nil || req.Tags != nil {
+		recipients += len(s.appPlayers(req.AppID))
+	}
+
+	res := &onesignal.NotificationCreateResponse{Recipients: recipients}
+	switch {
+	case len(invalidPlayerIDs) > 0:
+		res.Errors = map[string][]string{"invalid_player_ids": invalidPlayerIDs}
+	case recipients == 0:
+		res.Errors = []string{"All included players are not subscribed"}
+	}
+	if recipients > 0 {
+		now := time.Unix(time.Now().Unix(), 0)
+		n := &notification{
+			appID:   req.AppID,
+			request: req,
+			Notification: onesignal.Notification{
+				ID:         s.newID(),
+				Successful: recipients,
+				QueuedAt:   now,
+				SendAfter:  now,
+				URL:        req.URL,
+				Data:       req.Data,
+				Headings:   req.Headings,
+				Contents:   req.Contents,
+			},
+		}
+		s.notifications = append(s.notifications, n)
+		res.ID = n.ID
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) updateNotification(w http.ResponseWriter, r *http.Request) {
+	var opt onesignal.NotificationUpdateOptions
+	if !decodeBody(w, r, &opt) {
+		return
+	}
+	n := s.notification(opt.AppID, r.PathValue("id"))
+	if n == nil {
+		writeErrors(w, http.StatusNotFound, "Could not find notification with id: "+r.PathValue("id"))
+		return
+	}
+	if opt.Opened {
+		n.Converted++
+	}
+	writeSuccess(w)
+}
+
+func (s *Server) deleteNotification(w http.ResponseWriter, r *http.Request) {
+	var opt onesignal.NotificationDeleteOptions
+	if !decodeBody(w, r, &opt) {
+		return
+	}
+	n := s.notification(opt.AppID, r.PathValue("id"))
+	if n == nil {
+		writeErrors(w, http.StatusNotFound, "Could not find notification with id: "+r.PathValue("id"))
+		return
+	}
+	n.Canceled = true
+	writeSuccess(w)
+}
@@ -0,0 +1,100 @@
+package onesignaltest
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/tbalthazar/onesignal-go"
+)
+
+func TestServer_notifications(t *testing.T) {
+	srv, client, appID := setup(t)
+
+	playerID := srv.AddPlayer(appID, onesignal.Player{})
+	srv.AddPlayer(appID, onesignal.Player{})
+
+	createRes, _, err := client.Notifications.Create(&onesignal.NotificationRequest{
+		AppID:            appID,
+		Contents:         map[string]string{"en": "Hello"},
+		IncludePlayerIDs: []string{playerID},
+	})
+	if err != nil {
+		t.Fatalf("Notifications.Create returned an error: %v", err)
+	}
+	if createRes.ID == "" || createRes.Recipients != 1 || createRes.Err() != nil {
+		t.Errorf("Notifications.Create returned %+v", createRes)
+	}
+
+	segmentRes, _, err := client.Notifications.Create(&onesignal.NotificationRequest{
+		AppID:            appID,
+		Contents:         map[string]string{"en": "Hello everyone"},
+		IncludedSegments: []string{"All"},
+	})
+	if err != nil {
+		t.Fatalf("Notifications.Create returned an error: %v", err)
+	}
+	if got, want := segmentRes.Recipients, 2; got != want {
+		t.Errorf("Recipients is %d, want %d", got, want)
+	}
+
+	if _, _, err := client.Notifications.Update(createRes.ID, &onesignal.NotificationUpdateOptions{AppID: appID, Opened: true}); err != nil {
+		t.Fatalf("Notifications.Update returned an error: %v", err)
+	}
+	if _, _, err := client.Notifications.Delete(segmentRes.ID, &onesignal.NotificationDeleteOptions{AppID: appID}); err != nil {
+		t.Fatalf("Notifications.Delete returned an error: %v", err)
+	}
+
+	notification, _, err := client.Notifications.Get(createRes.ID, &onesignal.NotificationGetOptions{AppID: appID})
+	if err != nil {
+		t.Fatalf("Notifications.Get returned an error: %v", err)
+	}
+	if notification.Successful != 1 || notification.Converted != 1 || notification.QueuedAt.IsZero() {
+		t.Errorf("Notifications.Get returned %+v", notification)
+	}
+	if got, want := notification.Contents, map[string]string{"en": "Hello"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Contents are %v, want %v", got, want)
+	}
+
+	listRes, _, err := client.Notifications.List(&onesignal.NotificationListOptions{AppID: appID})
+	if err != nil {
+		t.Fatalf("Notifications.List returned an error: %v", err)
+	}
+	if listRes.TotalCount != 2 || len(listRes.Notifications) != 2 {
+		t.Fatalf("Notifications.List returned %+v", listRes)
+	}
+	if got := listRes.Notifications[0]; got.ID != segmentRes.ID || !got.Canceled {
+		t.Errorf("Notifications.List returned %+v first, want the canceled %v", got, segmentRes.ID)
+	}
+	if got := srv.Notifications(appID); !reflect.DeepEqual(got, listRes.Notifications) {
+		t.Errorf("Notifications returned %+v, want %+v", got, listRes.Notifications)
+	}
+
+	req, ok := srv.NotificationRequest(segmentRes.ID)
+	if !ok || !reflect.DeepEqual(req.IncludedSegments, []string{"All"}) {
+		t.Errorf("NotificationRequest returned %+v, %v", req, ok)
+	}
+}
+
+func TestServer_notifications_invalidPlayerIDs(t *testing.T) {
+	_, client, appID := setup(t)
+
+	createRes, _, err := client.Notifications.Create(&onesignal.NotificationRequest{
+		AppID:            appID,
+		Contents:         map[string]string{"en": "Hello"},
+		IncludePlayerIDs: []string{"unknown"},
+	})
+	if err != nil {
+		t.Fatalf("Notifications.Create returned an error: %v", err)
+	}
+	if createRes.ID != "" || createRes.Recipients != 0 {
+		t.Errorf("Notifications.Create returned %+v", createRes)
+	}
+	if err := createRes.Err(); !errors.Is(err, onesignal.ErrInvalidPlayerIDs) {
+		t.Errorf("Err returned %v, want %v", err, onesignal.ErrInvalidPlayerIDs)
+	}
+
+	if _, _, err := client.Notifications.Get("unknown", &onesignal.NotificationGetOptions{AppID: appID}); !errors.Is(err, onesignal.ErrNotFound) {
+		t.Errorf("Notifications.Get returned %v, want %v", err, onesignal.ErrNotFound)
+	}
+}
@@ -0,0 +1,215 @@
+// Package onesignaltest provides an in-memory fake of the OneSignal API, to
+// test code using a onesignal.Client without reaching the network.
+//
+// A Server implements the apps, players and notifications endpoints and keeps
+// their state in memory. It checks the keys of the requests, generates IDs and
+// paginates lists like OneSignal does:
+//
+//	srv := onesignaltest.NewServer()
+//	defer srv.Close()
+//
+//	client, err := srv.Client()
+//	appID := srv.AddApp(onesignal.App{Name: "My app"})
+//	createRes, _, err := client.Players.Create(&onesignal.PlayerRequest{
+//		AppID:      appID,
+//		DeviceType: onesignal.DeviceTypeAndroid,
+//	})
+//
+// Faults such as latency or 429 and 500 responses can be injected to exercise
+// timeouts, retries and error handling:
+//
+//	srv.InjectFault(onesignaltest.Fault{
+//		Path:       "/notifications",
+//		StatusCode: http.StatusTooManyRequests,
+//		RetryAfter: time.Second,
+//		Times:      1,
+//	})
+package onesignaltest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+
+	"github.com/tbalthazar/onesignal-go"
+)
+
+// Keys expected by a Server unless others are given with WithAppKey and
+// WithUserKey.
+const (
+	DefaultAppKey  = "test-app-key"
+	DefaultUserKey = "test-user-key"
+)
+
+// Server is a fake OneSignal API listening on a local address. It is safe
+// for concurrent use.
+type Server struct {
+	// URL is the base URL of the server, to give to onesignal.WithBaseURL.
+	URL string
+
+	// AppKey and UserKey are the keys the requests must be authorized
+	// with, depending on their onesignal.AuthKeyType.
+	AppKey  string
+	UserKey string
+
+	srv *httptest.Server
+
+	mu            sync.Mutex
+	lastID        int
+	requests      int
+	faults        []*Fault
+	apps          []*onesignal.App
+	players       []*player
+	notifications []*notification
+	exports       map[string][]byte
+}
+
+// An Option configures a Server created by NewServer.
+type Option func(*Server)
+
+// WithAppKey sets the key expected for the requests authorized with
+// onesignal.APP.
+func WithAppKey(key string) Option {
+	return func(s *Server) {
+		s.AppKey = key
+	}
+}
+
+// WithUserKey sets the key expected for the requests authorized with
+// onesignal.USER, such as the /apps endpoints.
+func WithUserKey(key string) Option {
+	return func(s *Server) {
+		s.UserKey = key
+	}
+}
+
+// NewServer starts and returns a new Server, with no apps. The caller should
+// call Close when finished, to shut it down.
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		AppKey:  DefaultAppKey,
+		UserKey: DefaultUserKey,
+		exports: make(map[string][]byte),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	mux := http.NewServeMux()
+	s.handle(mux, "GET /apps", onesignal.USER, s.listApps)
+	s.handle(mux, "GET /apps/{id}", onesignal.USER, s.getApp)
+	s.handle(mux, "POST /apps", onesignal.USER, s.createApp)
+	s.handle(mux, "PUT /apps/{id}", onesignal.USER, s.updateApp)
+	s.handle(mux, "GET /players", onesignal.APP, s.listPlayers)
+	s.handle(mux, "GET /players/{id}", onesignal.APP, s.getPlayer)
+	s.handle(mux, "POST /players", onesignal.APP, s.createPlayer)
+	s.handle(mux, "PUT /players/{id}", onesignal.APP, s.updatePlayer)
+	s.handle(mux, "POST /players/{id}/on_session", onesignal.APP, s.onSession)
+	s.handle(mux, "POST /players/{id}/on_purchase", onesignal.APP, s.onPurchase)
+	s.handle(mux, "POST /players/{id}/on_focus", onesignal.APP, s.onFocus)
+	s.handle(mux, "POST /players/csv_export", onesignal.APP, s.csvExport)
+	s.handle(mux, "GET /notifications", onesignal.APP, s.listNotifications)
+	s.handle(mux, "GET /notifications/{id}", onesignal.APP, s.getNotification)
+	s.handle(mux, "POST /notifications", onesignal.APP, s.createNotification)
+	s.handle(mux, "PUT /notifications/{id}", onesignal.APP, s.updateNotification)
+	s.handle(mux, "DELETE /notifications/{id}", onesignal.APP, s.deleteNotification)
+
+	// exports are downloaded from a bucket, without any key
+	mux.HandleFunc("GET /csv_exports/{file}", s.downloadExport)
+
+	s.srv = httptest.NewServer(s.intercept(mux))
+	s.URL = s.srv.URL
+	return s
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Client returns a onesignal.Client sending its requests to s with the
+// right keys. The opts are applied after the ones setting the keys and the
+// base URL.
+func (s *Server) Client(opts ...onesignal.Option) (*onesignal.Client, error) {
+	return onesignal.NewClient(append([]onesignal.Option{
+		onesignal.WithAppKey(s.AppKey),
+		onesignal.WithUserKey(s.UserKey),
+		onesignal.WithBaseURL(s.URL),
+	}, opts...)...)
+}
+
+// Requests returns the number of requests s has received, including the
+// ones failed by a Fault or rejected for a wrong key.
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+// handle registers h for pattern. The requests must be authorized with the
+// key of authKeyType, and h is called with s locked.
+func (s *Server) handle(mux *http.ServeMux, pattern string, authKeyType onesignal.AuthKeyType, h http.HandlerFunc) {
+	mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		key := s.AppKey
+		if authKeyType == onesignal.USER {
+			key = s.UserKey
+		}
+		if r.Header.Get("Authorization") != "Basic "+key {
+			writeErrors(w, http.StatusUnauthorized, "Please include a case-sensitive header of Authorization: Basic <YOUR-REST-API-KEY-HERE> with a valid REST API key.")
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		h(w, r)
+	})
+}
+
+// newID returns a new ID shaped like a UUID. s must be locked.
+func (s *Server) newID() string {
+	s.lastID++
+	return fmt.Sprintf("00000000-0000-4000-8000-%012x", s.lastID)
+}
+
+// paginate returns the bounds of the page of a list of total items asked for
+// by the limit and offset query parameters of r. Limits that are missing,
+// not positive or above maxLimit are set to maxLimit.
+func paginate(r *http.Request, total, maxLimit int) (start, end, limit int) {
+	limit, _ = strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit <= 0 || limit > maxLimit {
+		limit = maxLimit
+	}
+	start, _ = strconv.Atoi(r.URL.Query().Get("offset"))
+	start = min(max(start, 0), total)
+	end = min(start+limit, total)
+	return start, end, limit
+}
+
+// decodeBody decodes the JSON body of r into v, and replies with a 400 error
+// if it can't.
+func decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeErrors(w, http.StatusBadRequest, "Invalid JSON: "+err.Error())
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeErrors replies with the messages in the format of the OneSignal API
+// errors.
+func writeErrors(w http.ResponseWriter, status int, messages ...string) {
+	writeJSON(w, status, map[string][]string{"errors": messages})
+}
+
+func writeSuccess(w http.ResponseWriter) {
+	writeJSON(w, http.StatusOK, onesignal.SuccessResponse{Success: true})
+}
@@ -0,0 +1,64 @@
+package onesignaltest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/tbalthazar/onesignal-go"
+)
+
+// setup starts a server with an app, and returns a client for it along with
+// the ID of the app.
+func setup(t *testing.T) (*Server, *onesignal.Client, string) {
+	srv := NewServer()
+	t.Cleanup(srv.Close)
+
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatalf("Client returned an error: %v", err)
+	}
+	appID := srv.AddApp(onesignal.App{Name: "Test app"})
+	return srv, client, appID
+}
+
+func TestServer_keys(t *testing.T) {
+	srv := NewServer(WithAppKey("my-app-key"), WithUserKey("my-user-key"))
+	defer srv.Close()
+	appID := srv.AddApp(onesignal.App{Name: "Test app"})
+
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatalf("Client returned an error: %v", err)
+	}
+	if _, _, err := client.Apps.Get(appID); err != nil {
+		t.Errorf("Apps.Get returned an error: %v", err)
+	}
+	if _, _, err := client.Players.List(&onesignal.PlayerListOptions{AppID: appID}); err != nil {
+		t.Errorf("Players.List returned an error: %v", err)
+	}
+
+	// the app key doesn't authorize the /apps endpoints, and vice versa
+	swapped, _ := srv.Client(onesignal.WithAppKey("my-user-key"), onesignal.WithUserKey("my-app-key"))
+	if _, _, err := swapped.Apps.Get(appID); !errors.Is(err, onesignal.ErrUnauthorized) {
+		t.Errorf("Apps.Get returned %v, want %v", err, onesignal.ErrUnauthorized)
+	}
+	if _, _, err := swapped.Players.List(&onesignal.PlayerListOptions{AppID: appID}); !errors.Is(err, onesignal.ErrUnauthorized) {
+		t.Errorf("Players.List returned %v, want %v", err, onesignal.ErrUnauthorized)
+	}
+
+	if got, want := srv.Requests(), 4; got != want {
+		t.Errorf("Requests returned %d, want %d", got, want)
+	}
+}
+
+func TestNewServer_defaultKeys(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	if got, want := srv.AppKey, DefaultAppKey; got != want {
+		t.Errorf("AppKey is %v, want %v", got, want)
+	}
+	if got, want := srv.UserKey, DefaultUserKey; got != want {
+		t.Errorf("UserKey is %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,312 @@
+package onesignaltest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/tbalthazar/onesignal-go"
+)
+
+// maxPlayersLimit is the maximum number of players listed per page.
+const maxPlayersLimit = 300
+
+// player is a onesignal.Player along with the app it belongs to.
+type player struct {
+	appID string
+	onesignal.Player
+}
+
+// exportColumns are the columns of a players export, before the extra
+// fields.
+var exportColumns = []string{
+	"id", "identifier", "session_count", "language", "timezone",
+	"game_version", "device_os", "device_type", "device_model", "ad_id",
+	"tags", "last_active", "playtime", "amount_spent", "created_at",
+	"invalid_identifier", "badge_count", "sdk",
+}
+
+// AddPlayer adds p to the app appID of s, as if it had been created with the
+// API, and returns its ID. An ID is generated if p has none.
+func (s *Server) AddPlayer(appID string, p onesignal.Player) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addPlayer(appID, p)
+}
+
+func (s *Server) addPlayer(appID string, p onesignal.Player) string {
+	if p.ID == "" {
+		p.ID = s.newID()
+	}
+	if p.CreatedAt == 0 {
+		p.CreatedAt = int(time.Now().Unix())
+	}
+	p.Tags = maps.Clone(p.Tags)
+	s.players = append(s.players, &player{appID: appID, Player: p})
+	return p.ID
+}
+
+// Players returns the players of the app appID, in the order they were
+// created.
+func (s *Server) Players(appID string) []onesignal.Player {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var players []onesignal.Player
+	for _, p := range s.appPlayers(appID) {
+		players = append(players, p.copy())
+	}
+	return players
+}
+
+// copy returns a copy of the onesignal.Player of p that doesn't share its
+// tags.
+func (p *player) copy() onesignal.Player {
+	c := p.Player
+	c.Tags = maps.Clone(p.Tags)
+	return c
+}
+
+// appPlayers returns the players of the app appID. s must be locked.
+func (s *Server) appPlayers(appID string) []*player {
+	var players []*player
+	for _, p := range s.players {
+		if p.appID == appID {
+			players = append(players, p)
+		}
+	}
+	return players
+}
+
+// player returns the player with this ID, or nil. s must be locked.
+func (s *Server) player(id string) *player {
+	for _, p := range s.players {
+		if p.ID == id {
+			return p
+		}
+	}
+	return nil
+}
+
+// applyPlayer merges the JSON body of r into p, and replies with a 400 error
+// if it can't.
+func applyPlayer(w http.ResponseWriter, r *http.Request, p *player) bool {
+	var body json.RawMessage
+	if !decodeBody(w, r, &body) {
+		return false
+	}
+	return mergePlayer(w, body, p)
+}
+
+// mergePlayer overwrites the fields of p with the ones set in body, and
+// replies with a 400 error if it can't. Tags are merged into the existing
+// ones, and tags set to an empty string are removed, as with the OneSignal
+// API.
+func mergePlayer(w http.ResponseWriter, body json.RawMessage, p *player) bool {
+	updated := p.copy()
+	if err := json.Unmarshal(body, &updated); err != nil {
+		writeErrors(w, http.StatusBadRequest, "Invalid JSON: "+err.Error())
+		return false
+	}
+	for key, value := range updated.Tags {
+		if value == "" {
+			delete(updated.Tags, key)
+		}
+	}
+	updated.ID = p.ID
+	p.Player = updated
+	return true
+}
+
+func (s *Server) listPlayers(w http.ResponseWriter, r *http.Request) {
+	appID := r.URL.Query().Get("app_id")
+	if s.app(appID) == nil {
+		writeErrors(w, http.StatusBadRequest, "app_id not found")
+		return
+	}
+
+	all := s.appPlayers(appID)
+	start, end, limit := paginate(r, len(all), maxPlayersLimit)
+	res := &onesignal.PlayerListResponse{
+		TotalCount: len(all),
+		Offset:     start,
+		Limit:      limit,
+		Players:    []onesignal.Player{},
+	}
+	for _, p := range all[start:end] {
+		res.Players = append(res.Players, p.Player)
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) getPlayer(w http.ResponseWriter, r *http.Request) {
+	p := s.player(r.PathValue("id"))
+	if p == nil {
+		writeErrors(w, http.StatusNotFound, "No user with this id found")
+		return
+	}
+	writeJSON(w, http.StatusOK, p.Player)
+}
+
+func (s *Server) createPlayer(w http.ResponseWriter, r *http.Request) {
+	var body json.RawMessage
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	var req onesignal.PlayerRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeErrors(w, http.StatusBadRequest, "Invalid JSON: "+err.Error())
+		return
+	}
+	if s.app(req.AppID) == nil {
+		writeErrors(w, http.StatusBadRequest, "app_id not found")
+		return
+	}
+
+	// a device registered again keeps its player
+	if req.Identifier != "" {
+		for _, p := range s.appPlayers(req.AppID) {
+			if p.Identifier == req.Identifier {
+				if mergePlayer(w, body, p) {
+					writeJSON(w, http.StatusOK, onesignal.PlayerCreateResponse{Success: true, ID: p.ID})
+				}
+				return
+			}
+		}
+	}
+
+	p := &player{appID: req.AppID}
+	if !mergePlayer(w, body, p) {
+		return
+	}
+	id := s.addPlayer(req.AppID, p.Player)
+	writeJSON(w, http.StatusOK, onesignal.PlayerCreateResponse{Success: true, ID: id})
+}
+
+func (s *Server) updatePlayer(w http.ResponseWriter, r *http.Request) {
+	p := s.player(r.PathValue("id"))
+	if p == nil {
+		writeErrors(w, http.StatusNotFound, "No user with this id found")
+		return
+	}
+	if !applyPlayer(w, r, p) {
+		return
+	}
+	writeSuccess(w)
+}
+
+func (s *Server) onSession(w http.ResponseWriter, r *http.Request) {
+	p := s.player(r.PathValue("id"))
+	if p == nil {
+		writeErrors(w, http.StatusNotFound, "No user with this id found")
+		return
+	}
+	if !applyPlayer(w, r, p) {
+		return
+	}
+	p.SessionCount++
+	p.LastActive = int(time.Now().Unix())
+	writeSuccess(w)
+}
+
+func (s *Server) onPurchase(w http.ResponseWriter, r *http.Request) {
+	p := s.player(r.PathValue("id"))
+	if p == nil {
+		writeErrors(w, http.StatusNotFound, "No user with this id found")
+		return
+	}
+	var opt onesignal.PlayerOnPurchaseOptions
+	if !decodeBody(w, r, &opt) {
+		return
+	}
+
+	// existing purchases were made before the player was created, and are
+	// already accounted for
+	if !opt.Existing {
+		for _, purchase := range opt.Purchases {
+			p.AmountSpent += purchase.Amount
+		}
+	}
+	writeSuccess(w)
+}
+
+func (s *Server) onFocus(w http.ResponseWriter, r *http.Request) {
+	p := s.player(r.PathValue("id"))
+	if p == nil {
+		writeErrors(w, http.StatusNotFound, "No user with this id found")
+		return
+	}
+	var opt onesignal.PlayerOnFocusOptions
+	if !decodeBody(w, r, &opt) {
+		return
+	}
+	if opt.State != "ping" {
+		writeErrors(w, http.StatusBadRequest, `state must be "ping"`)
+		return
+	}
+	p.Playtime += opt.ActiveTime
+	writeSuccess(w)
+}
+
+// csvExport generates the export right away, unlike OneSignal, and serves it
+// under /csv_exports.
+func (s *Server) csvExport(w http.ResponseWriter, r *http.Request) {
+	appID := r.URL.Query().Get("app_id")
+	if s.app(appID) == nil {
+		writeErrors(w, http.StatusBadRequest, "app_id not found")
+		return
+	}
+	var opt onesignal.PlayerCSVExportOptions
+	if !decodeBody(w, r, &opt) {
+		return
+	}
+	lastActiveSince, _ := strconv.Atoi(opt.LastActiveSince)
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	cw := csv.NewWriter(gz)
+	cw.Write(slices.Concat(exportColumns, opt.ExtraFields))
+	for _, p := range s.appPlayers(appID) {
+		if p.LastActive < lastActiveSince {
+			continue
+		}
+		tags, _ := json.Marshal(p.Tags)
+		if p.Tags == nil {
+			tags = nil
+		}
+		row := []string{
+			p.ID, p.Identifier, strconv.Itoa(p.SessionCount), p.Language, strconv.Itoa(p.Timezone),
+			p.GameVersion, p.DeviceOS, strconv.Itoa(p.DeviceType), p.DeviceModel, p.AdID,
+			string(tags), strconv.Itoa(p.LastActive), strconv.Itoa(p.Playtime),
+			strconv.FormatFloat(float64(p.AmountSpent), 'f', -1, 32), strconv.Itoa(p.CreatedAt),
+			strconv.FormatBool(p.InvalidIdentifier), strconv.Itoa(p.BadgeCount), p.SDK,
+		}
+		for range opt.ExtraFields {
+			row = append(row, "")
+		}
+		cw.Write(row)
+	}
+	cw.Flush()
+	gz.Close()
+
+	file := s.newID() + ".csv.gz"
+	s.exports[file] = buf.Bytes()
+	writeJSON(w, http.StatusOK, onesignal.PlayerCSVExportResponse{CSVFileURL: s.URL + "/csv_exports/" + file})
+}
+
+func (s *Server) downloadExport(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	b, ok := s.exports[r.PathValue("file")]
+	s.mu.Unlock()
+	if !ok {
+		http.Error(w, "NoSuchKey", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Write(b)
+}
@@ -0,0 +1,132 @@
+package onesignaltest
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/tbalthazar/onesignal-go"
+)
+
+func TestServer_players(t *testing.T) {
+	srv, client, appID := setup(t)
+
+	createRes, _, err := client.Players.Create(&onesignal.PlayerRequest{
+		AppID:      appID,
+		DeviceType: onesignal.DeviceTypeAndroid,
+		Identifier: "token1",
+		Tags:       map[string]string{"level": "1", "premium": "true"},
+	})
+	if err != nil {
+		t.Fatalf("Players.Create returned an error: %v", err)
+	}
+	playerID := createRes.ID
+
+	// the same device keeps its player
+	again, _, err := client.Players.Create(&onesignal.PlayerRequest{AppID: appID, DeviceType: onesignal.DeviceTypeAndroid, Identifier: "token1"})
+	if err != nil {
+		t.Fatalf("Players.Create returned an error: %v", err)
+	}
+	if got, want := again.ID, playerID; got != want {
+		t.Errorf("Players.Create returned the ID %v, want %v", got, want)
+	}
+
+	_, _, err = client.Players.Update(playerID, &onesignal.PlayerRequest{
+		AppID:      appID,
+		DeviceType: onesignal.DeviceTypeAndroid,
+		Language:   "fr",
+		Tags:       map[string]string{"level": "2", "premium": ""},
+	})
+	if err != nil {
+		t.Fatalf("Players.Update returned an error: %v", err)
+	}
+	if _, _, err := client.Players.OnSession(playerID, &onesignal.PlayerOnSessionOptions{Tags: map[string]string{"source": "ad"}}); err != nil {
+		t.Fatalf("Players.OnSession returned an error: %v", err)
+	}
+	purchases := &onesignal.PlayerOnPurchaseOptions{Purchases: []onesignal.Purchase{{SKU: "sku1", Amount: 1.5}, {SKU: "sku2", Amount: 2}}}
+	if _, _, err := client.Players.OnPurchase(playerID, purchases); err != nil {
+		t.Fatalf("Players.OnPurchase returned an error: %v", err)
+	}
+	if _, _, err := client.Players.OnFocus(playerID, &onesignal.PlayerOnFocusOptions{State: "ping", ActiveTime: 60}); err != nil {
+		t.Fatalf("Players.OnFocus returned an error: %v", err)
+	}
+
+	player, _, err := client.Players.Get(playerID)
+	if err != nil {
+		t.Fatalf("Players.Get returned an error: %v", err)
+	}
+	if got, want := player.Tags, map[string]string{"level": "2", "source": "ad"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Tags are %v, want %v", got, want)
+	}
+	if player.Language != "fr" || player.SessionCount != 1 || player.LastActive == 0 || player.AmountSpent != 3.5 || player.Playtime != 60 {
+		t.Errorf("Players.Get returned %+v", player)
+	}
+	if got := srv.Players(appID); len(got) != 1 || !reflect.DeepEqual(got[0], *player) {
+		t.Errorf("Players returned %+v, want [%+v]", got, *player)
+	}
+}
+
+func TestServer_players_pagination(t *testing.T) {
+	srv, client, appID := setup(t)
+
+	var want []string
+	for range 7 {
+		want = append(want, srv.AddPlayer(appID, onesignal.Player{}))
+	}
+	srv.AddPlayer(srv.AddApp(onesignal.App{Name: "Other app"}), onesignal.Player{})
+
+	listRes, _, err := client.Players.List(&onesignal.PlayerListOptions{AppID: appID, Limit: 3, Offset: 6})
+	if err != nil {
+		t.Fatalf("Players.List returned an error: %v", err)
+	}
+	if listRes.TotalCount != 7 || listRes.Offset != 6 || listRes.Limit != 3 || len(listRes.Players) != 1 {
+		t.Errorf("Players.List returned %+v", listRes)
+	}
+
+	var got []string
+	for player, err := range client.Players.All(context.Background(), appID, &onesignal.PageOptions{PageSize: 3}) {
+		if err != nil {
+			t.Fatalf("Players.All returned an error: %v", err)
+		}
+		got = append(got, player.ID)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Players.All returned %v, want %v", got, want)
+	}
+}
+
+func TestServer_players_export(t *testing.T) {
+	srv, client, appID := setup(t)
+
+	srv.AddPlayer(appID, onesignal.Player{ID: "player1", LastActive: 100, Tags: map[string]string{"level": "1"}})
+	srv.AddPlayer(appID, onesignal.Player{ID: "player2", LastActive: 200, AmountSpent: 1.5})
+
+	opt := &onesignal.PlayerExportOptions{AppID: appID, ExtraFields: []string{"country"}}
+	var got []onesignal.Player
+	for player, err := range client.Players.ExportAll(context.Background(), opt) {
+		if err != nil {
+			t.Fatalf("Players.ExportAll returned an error: %v", err)
+		}
+		got = append(got, player)
+	}
+
+	want := srv.Players(appID)
+	for i := range want {
+		want[i].Extra = map[string]string{"country": ""}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Players.ExportAll returned %+v, want %+v", got, want)
+	}
+}
+
+func TestServer_players_errors(t *testing.T) {
+	_, client, _ := setup(t)
+
+	if _, _, err := client.Players.Get("unknown"); !errors.Is(err, onesignal.ErrNotFound) {
+		t.Errorf("Players.Get returned %v, want %v", err, onesignal.ErrNotFound)
+	}
+	if _, _, err := client.Players.Create(&onesignal.PlayerRequest{AppID: "unknown"}); onesignal.ErrorCategory(err) != "client" {
+		t.Errorf("Players.Create returned %v, want a client error", err)
+	}
+}
@@ -0,0 +1,37 @@
+package onesignal
+
+import (
+	"context"
+	"net/http"
+)
+
+// Operation describes the logical API operation a request was created for,
+// such as "notifications.create" or "players.list". Middlewares can use it to
+// name spans or label metrics.
+type Operation struct {
+	// Name is the service and the method, e.g. "players.list".
+	Name string
+
+	// AppID is the OneSignal app the operation targets, if known.
+	AppID string
+
+	// Recipients is the number of devices explicitly targeted by a
+	// notification (player IDs, tokens and registration IDs). Zero for
+	// other operations, or when the notification targets segments or
+	// filters.
+	Recipients int
+}
+
+type operationContextKey struct{}
+
+// withOperation returns a copy of ctx carrying op.
+func withOperation(ctx context.Context, op Operation) context.Context {
+	return context.WithValue(ctx, operationContextKey{}, op)
+}
+
+// OperationFromRequest returns the Operation r was created for by one of the
+// services of the Client. ok is false if r wasn't created by a service.
+func OperationFromRequest(r *http.Request) (op Operation, ok bool) {
+	op, ok = r.Context().Value(operationContextKey{}).(Operation)
+	return op, ok
+}
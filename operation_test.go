@@ -0,0 +1,131 @@
+package onesignal
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOperationFromRequest(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var got Operation
+	var ok bool
+	client.Use(func(next Doer) Doer {
+		return DoerFunc(func(r *http.Request) (*http.Response, error) {
+			got, ok = OperationFromRequest(r)
+			return next.Do(r)
+		})
+	})
+
+	mux.HandleFunc("/notifications", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": "notif-fake-id", "recipients": 1}`)
+	})
+
+	notificationRequest := &NotificationRequest{
+		AppID:            "id123",
+		Contents:         map[string]string{"en": "English message"},
+		IncludePlayerIDs: []string{"playerid1", "playerid2"},
+		IncludeIOSTokens: []string{"token1"},
+	}
+	client.Notifications.Create(notificationRequest)
+
+	if !ok {
+		t.Fatalf("OperationFromRequest should find an operation")
+	}
+	want := Operation{Name: "notifications.create", AppID: "id123", Recipients: 3}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("OperationFromRequest returned %+v, want %+v", got, want)
+	}
+}
+
+func TestOperationFromRequest_notFromAService(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/", nil)
+
+	if _, ok := OperationFromRequest(req); ok {
+		t.Errorf("OperationFromRequest should not find an operation")
+	}
+}
+
+func TestServices_nilRequest(t *testing.T) {
+	setup()
+	defer teardown()
+
+	// the services send a null body or no query string for a nil request,
+	// as before operations were recorded
+	var bodies []string
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, r.Method+" "+r.URL.String()+" "+strings.TrimSpace(string(b)))
+		fmt.Fprint(w, `{}`)
+	})
+
+	noValidation, _ := NewClient(WithBaseURL(server.URL), WithRequestValidation(false))
+	calls := map[string]func() error{
+		"Notifications.Create": func() error {
+			_, _, err := noValidation.Notifications.Create(nil)
+			return err
+		},
+		"Notifications.Update": func() error {
+			_, _, err := client.Notifications.Update("notif1", nil)
+			return err
+		},
+		"Notifications.Delete": func() error {
+			_, _, err := client.Notifications.Delete("notif1", nil)
+			return err
+		},
+		"Players.Create": func() error {
+			_, _, err := client.Players.Create(nil)
+			return err
+		},
+		"Players.Update": func() error {
+			_, _, err := client.Players.Update("player1", nil)
+			return err
+		},
+		"Templates.List": func() error {
+			_, _, err := client.Templates.List(nil)
+			return err
+		},
+		"Templates.Get": func() error {
+			_, _, err := client.Templates.Get("template1", nil)
+			return err
+		},
+		"Templates.Create": func() error {
+			_, _, err := client.Templates.Create(nil)
+			return err
+		},
+		"Templates.Update": func() error {
+			_, _, err := client.Templates.Update("template1", nil)
+			return err
+		},
+		"Templates.Delete": func() error {
+			_, _, err := client.Templates.Delete("template1", nil)
+			return err
+		},
+	}
+	for name, call := range calls {
+		if err := call(); err != nil {
+			t.Errorf("%s(nil) returned an error: %v", name, err)
+		}
+	}
+	if len(bodies) != len(calls) {
+		t.Errorf("the server received %d requests, want %d: %q", len(bodies), len(calls), bodies)
+	}
+	for _, body := range bodies {
+		method, _, _ := strings.Cut(body, " ")
+		if (method == "POST" || method == "PUT" || method == "PATCH") && !strings.HasSuffix(body, " null") {
+			t.Errorf("request %q should have a null body", body)
+		}
+	}
+
+	// a nil notification is invalid, rather than a panic
+	var validationErr *ValidationError
+	if _, _, err := client.Notifications.Create(nil); !errors.As(err, &validationErr) {
+		t.Errorf("Notifications.Create(nil) returned %v, want a *ValidationError", err)
+	}
+}
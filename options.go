@@ -0,0 +1,150 @@
+package onesignal
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// An Option configures a Client created by NewClient.
+type Option func(*Client) error
+
+// WithAppKey sets the key used for the requests authorized with APP.
+func WithAppKey(key string) Option {
+	return func(c *Client) error {
+		if key == "" {
+			return errors.New("onesignal: empty app key")
+		}
+		c.AppKey = key
+		return nil
+	}
+}
+
+// WithUserKey sets the key used for the requests authorized with USER, such
+// as the /apps endpoints.
+func WithUserKey(key string) Option {
+	return func(c *Client) error {
+		if key == "" {
+			return errors.New("onesignal: empty user key")
+		}
+		c.UserKey = key
+		return nil
+	}
+}
+
+// WithBaseURL sets the base URL of the OneSignal API, which defaults to
+// https://onesignal.com/api/v1/.
+func WithBaseURL(rawurl string) Option {
+	return func(c *Client) error {
+		u, err := url.Parse(rawurl)
+		if err != nil {
+			return fmt.Errorf("onesignal: invalid base URL: %v", err)
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("onesignal: invalid base URL %q: scheme and host are required", rawurl)
+		}
+		c.BaseURL = u
+		return nil
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to send the requests, which
+// defaults to http.DefaultClient.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) error {
+		if httpClient == nil {
+			return errors.New("onesignal: nil HTTP client")
+		}
+		c.Client = httpClient
+		return nil
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) error {
+		c.UserAgent = userAgent
+		return nil
+	}
+}
+
+// WithRetryPolicy sets the policy used to retry failed requests. A nil
+// policy disables retries.
+func WithRetryPolicy(p *RetryPolicy) Option {
+	return func(c *Client) error {
+		c.RetryPolicy = p
+		return nil
+	}
+}
+
+// WithRateLimiter throttles the requests authorized with authKeyType with l,
+// as returned by NewRateLimiter.
+func WithRateLimiter(authKeyType AuthKeyType, l *RateLimiter) Option {
+	return func(c *Client) error {
+		if l == nil {
+			return errors.New("onesignal: nil rate limiter")
+		}
+		if err := l.validate(); err != nil {
+			return err
+		}
+		if c.RateLimiters == nil {
+			c.RateLimiters = make(map[AuthKeyType]*RateLimiter)
+		}
+		c.RateLimiters[authKeyType] = l
+		return nil
+	}
+}
+
+// WithLogger sets the logger the client writes debug messages to: the
+// method, path, status, duration and response size of every request sent.
+// By default nothing is logged, not even to the global logger.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) error {
+		if logger == nil {
+			return errors.New("onesignal: nil logger")
+		}
+		c.logger = logger
+		return nil
+	}
+}
+
+// WithBodyLogging makes the client also log request and response bodies.
+// The AppKey, the UserKey and secrets such as the APNS certificates and
+// passwords or the basic_auth_key are always redacted.
+func WithBodyLogging(enabled bool) Option {
+	return func(c *Client) error {
+		c.logBodies = enabled
+		return nil
+	}
+}
+
+// WithRequestValidation enables or disables the validation of the requests
+// before they are sent, such as NotificationRequest.Validate in
+// NotificationsService.Create. It is enabled by default.
+func WithRequestValidation(enabled bool) Option {
+	return func(c *Client) error {
+		c.skipValidation = !enabled
+		return nil
+	}
+}
+
+// NewClientFromEnv returns a new OneSignal API client configured from the
+// ONESIGNAL_APP_KEY, ONESIGNAL_USER_KEY and ONESIGNAL_API_URL environment
+// variables. Unset variables are ignored. opts are applied after the
+// environment, so they take precedence.
+func NewClientFromEnv(opts ...Option) (*Client, error) {
+	var envOpts []Option
+	if key := os.Getenv("ONESIGNAL_APP_KEY"); key != "" {
+		envOpts = append(envOpts, WithAppKey(key))
+	}
+	if key := os.Getenv("ONESIGNAL_USER_KEY"); key != "" {
+		envOpts = append(envOpts, WithUserKey(key))
+	}
+	if rawurl := os.Getenv("ONESIGNAL_API_URL"); rawurl != "" {
+		envOpts = append(envOpts, WithBaseURL(rawurl))
+	}
+	return NewClient(append(envOpts, opts...)...)
+}
@@ -0,0 +1,112 @@
+package onesignal
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestNewClient_withOptions(t *testing.T) {
+	httpClient := &http.Client{}
+	retryPolicy := DefaultRetryPolicy()
+	limiter, err := NewRateLimiter(1, 1)
+	if err != nil {
+		t.Fatalf("NewRateLimiter returned an error: %v", err)
+	}
+
+	c, err := NewClient(
+		WithAppKey("fake-app-key"),
+		WithUserKey("fake-user-key"),
+		WithBaseURL("https://example.com/api/v1/"),
+		WithHTTPClient(httpClient),
+		WithUserAgent("my-app/1.0"),
+		WithRetryPolicy(retryPolicy),
+		WithRateLimiter(APP, limiter),
+	)
+	if err != nil {
+		t.Fatalf("NewClient returned an error: %v", err)
+	}
+
+	if got, want := c.AppKey, "fake-app-key"; got != want {
+		t.Errorf("NewClient AppKey is %v, want %v", got, want)
+	}
+	if got, want := c.UserKey, "fake-user-key"; got != want {
+		t.Errorf("NewClient UserKey is %v, want %v", got, want)
+	}
+	if got, want := c.BaseURL.String(), "https://example.com/api/v1/"; got != want {
+		t.Errorf("NewClient BaseURL is %v, want %v", got, want)
+	}
+	if got, want := c.Client, httpClient; got != want {
+		t.Errorf("NewClient Client is %v, want %v", got, want)
+	}
+	if got, want := c.UserAgent, "my-app/1.0"; got != want {
+		t.Errorf("NewClient UserAgent is %v, want %v", got, want)
+	}
+	if got, want := c.RetryPolicy, retryPolicy; got != want {
+		t.Errorf("NewClient RetryPolicy is %v, want %v", got, want)
+	}
+	if got, want := c.RateLimiters[APP], limiter; got != want {
+		t.Errorf("NewClient RateLimiters[APP] is %v, want %v", got, want)
+	}
+}
+
+func TestNewClient_invalidOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  Option
+	}{
+		{"empty app key", WithAppKey("")},
+		{"empty user key", WithUserKey("")},
+		{"unparsable base URL", WithBaseURL("http://[::1")},
+		{"relative base URL", WithBaseURL("/api/v1")},
+		{"nil HTTP client", WithHTTPClient(nil)},
+		{"nil rate limiter", WithRateLimiter(USER, nil)},
+		{"zero rate limiter", WithRateLimiter(USER, &RateLimiter{})},
+		{"nil logger", WithLogger(nil)},
+	}
+	for _, tt := range tests {
+		c, err := NewClient(tt.opt)
+		if err == nil {
+			t.Errorf("NewClient with %s should return an error", tt.name)
+		}
+		if c != nil {
+			t.Errorf("NewClient with %s should return a nil Client", tt.name)
+		}
+	}
+}
+
+func TestNewClientFromEnv(t *testing.T) {
+	t.Setenv("ONESIGNAL_APP_KEY", "env-app-key")
+	t.Setenv("ONESIGNAL_USER_KEY", "env-user-key")
+	t.Setenv("ONESIGNAL_API_URL", "https://example.com/api/v1/")
+
+	c, err := NewClientFromEnv(WithUserKey("explicit-user-key"))
+	if err != nil {
+		t.Fatalf("NewClientFromEnv returned an error: %v", err)
+	}
+
+	if got, want := c.AppKey, "env-app-key"; got != want {
+		t.Errorf("NewClientFromEnv AppKey is %v, want %v", got, want)
+	}
+	if got, want := c.UserKey, "explicit-user-key"; got != want {
+		t.Errorf("NewClientFromEnv UserKey is %v, want %v", got, want)
+	}
+	if got, want := c.BaseURL.String(), "https://example.com/api/v1/"; got != want {
+		t.Errorf("NewClientFromEnv BaseURL is %v, want %v", got, want)
+	}
+}
+
+func TestNewClientFromEnv_invalidURL(t *testing.T) {
+	t.Setenv("ONESIGNAL_API_URL", "not a url")
+
+	if _, err := NewClientFromEnv(); err == nil {
+		t.Errorf("NewClientFromEnv should return an error")
+	}
+}
+
+func TestNewRequest_userAgent(t *testing.T) {
+	c, _ := NewClient(WithUserAgent("my-app/1.0"))
+
+	req, _ := c.NewRequest("GET", "foo", nil, APP)
+
+	testHeader(t, req, "User-Agent", "my-app/1.0")
+}
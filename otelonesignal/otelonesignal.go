@@ -0,0 +1,165 @@
+// Package otelonesignal instruments a onesignal.Client with OpenTelemetry.
+//
+// It provides a middleware creating a span per logical operation, such as
+// notifications.create or players.list, and recording the latency and the
+// errors of every operation:
+//
+//	client.Use(otelonesignal.Middleware())
+//
+// The global TracerProvider and MeterProvider are used unless others are
+// given with WithTracerProvider and WithMeterProvider.
+package otelonesignal
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/tbalthazar/onesignal-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/tbalthazar/onesignal-go/otelonesignal"
+
+// Attribute keys set on spans and, for operation and error category, on
+// metrics.
+const (
+	OperationKey      = attribute.Key("onesignal.operation")
+	AppIDKey          = attribute.Key("onesignal.app_id")
+	RecipientsKey     = attribute.Key("onesignal.recipients")
+	ErrorCategoryKey  = attribute.Key("onesignal.error.category")
+	HTTPMethodKey     = attribute.Key("http.request.method")
+	HTTPStatusCodeKey = attribute.Key("http.response.status_code")
+)
+
+// An Option configures the middleware returned by Middleware.
+type Option func(*config)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+}
+
+// WithTracerProvider sets the TracerProvider used to create spans.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = tp
+	}
+}
+
+// WithMeterProvider sets the MeterProvider used to record metrics.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = mp
+	}
+}
+
+// Middleware returns a onesignal.Middleware that traces every call to
+// Client.Do and records the following metrics, labeled by operation and
+// error category:
+//
+//   - onesignal.client.duration, a histogram of the operations latency in
+//     seconds, retries included,
+//   - onesignal.client.errors, a counter of the failed operations.
+//
+// It should be the first middleware added to the client so that its spans
+// cover the other middlewares.
+func Middleware(opts ...Option) onesignal.Middleware {
+	c := config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	tracer := c.tracerProvider.Tracer(instrumentationName)
+	meter := c.meterProvider.Meter(instrumentationName)
+
+	// on error, the instruments returned are still usable no-ops
+	duration, err := meter.Float64Histogram("onesignal.client.duration",
+		metric.WithDescription("Duration of the OneSignal API operations."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+	errorCount, err := meter.Int64Counter("onesignal.client.errors",
+		metric.WithDescription("Number of failed OneSignal API operations."),
+		metric.WithUnit("{error}"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	return func(next onesignal.Doer) onesignal.Doer {
+		return onesignal.DoerFunc(func(r *http.Request) (*http.Response, error) {
+			name := "onesignal " + r.Method
+			op, ok := onesignal.OperationFromRequest(r)
+			if ok {
+				name = op.Name
+			}
+
+			spanAttrs := []attribute.KeyValue{HTTPMethodKey.String(r.Method)}
+			if ok {
+				spanAttrs = append(spanAttrs, OperationKey.String(op.Name))
+				if op.AppID != "" {
+					spanAttrs = append(spanAttrs, AppIDKey.String(op.AppID))
+				}
+				if op.Recipients > 0 {
+					spanAttrs = append(spanAttrs, RecipientsKey.Int(op.Recipients))
+				}
+			}
+
+			ctx, span := tracer.Start(r.Context(), name,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(spanAttrs...),
+			)
+			defer span.End()
+
+			start := time.Now()
+			resp, err := next.Do(r.WithContext(ctx))
+			elapsed := time.Since(start)
+
+			category := errorCategory(resp, err)
+			if resp != nil {
+				span.SetAttributes(HTTPStatusCodeKey.Int(resp.StatusCode))
+			}
+
+			metricAttrs := []attribute.KeyValue{OperationKey.String(name)}
+			if category != "" {
+				span.SetAttributes(ErrorCategoryKey.String(category))
+				if err != nil {
+					span.RecordError(err)
+				}
+				span.SetStatus(codes.Error, category)
+				metricAttrs = append(metricAttrs, ErrorCategoryKey.String(category))
+			}
+
+			set := metric.WithAttributes(metricAttrs...)
+			duration.Record(ctx, elapsed.Seconds(), set)
+			if category != "" {
+				errorCount.Add(ctx, 1, set)
+			}
+
+			return resp, err
+		})
+	}
+}
+
+// errorCategory returns the category of the outcome of a request, using the
+// same names as onesignal.ErrorCategory, or an empty string on success.
+func errorCategory(resp *http.Response, err error) string {
+	if err != nil {
+		return onesignal.ErrorCategory(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		// the body is left for CheckResponse, so errors that can only be
+		// identified from it are reported as "client"
+		return onesignal.ErrorCategory(&onesignal.ErrorResponse{StatusCode: resp.StatusCode})
+	}
+	return ""
+}
@@ -0,0 +1,181 @@
+package otelonesignal
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tbalthazar/onesignal-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func setup(t *testing.T, handler http.HandlerFunc) (*onesignal.Client, *tracetest.InMemoryExporter, *sdkmetric.ManualReader) {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	client, err := onesignal.NewClient(
+		onesignal.WithAppKey("fake-app-key"),
+		onesignal.WithBaseURL(server.URL),
+		onesignal.WithMiddleware(Middleware(WithTracerProvider(tp), WithMeterProvider(mp))),
+	)
+	if err != nil {
+		t.Fatalf("NewClient returned an error: %v", err)
+	}
+	return client, exporter, reader
+}
+
+func spanAttr(attrs []attribute.KeyValue, key attribute.Key) (attribute.Value, bool) {
+	for _, kv := range attrs {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func collect(t *testing.T, reader *sdkmetric.ManualReader) map[string]metricdata.Aggregation {
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect returned an error: %v", err)
+	}
+	metrics := make(map[string]metricdata.Aggregation)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			metrics[m.Name] = m.Data
+		}
+	}
+	return metrics
+}
+
+func TestMiddleware_success(t *testing.T) {
+	client, exporter, reader := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": "notif-fake-id", "recipients": 2}`)
+	})
+
+	_, _, err := client.Notifications.Create(&onesignal.NotificationRequest{
+		AppID:            "id123",
+		Contents:         map[string]string{"en": "English message"},
+		IncludePlayerIDs: []string{"playerid1", "playerid2"},
+	})
+	if err != nil {
+		t.Fatalf("Create returned an error: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("Got %d spans, want 1", len(spans))
+	}
+	span := spans[0]
+	if got, want := span.Name, "notifications.create"; got != want {
+		t.Errorf("Span name is %v, want %v", got, want)
+	}
+	if got, want := span.Status.Code, codes.Unset; got != want {
+		t.Errorf("Span status is %v, want %v", got, want)
+	}
+
+	tests := []struct {
+		key  attribute.Key
+		want attribute.Value
+	}{
+		{OperationKey, attribute.StringValue("notifications.create")},
+		{AppIDKey, attribute.StringValue("id123")},
+		{RecipientsKey, attribute.IntValue(2)},
+		{HTTPMethodKey, attribute.StringValue("POST")},
+		{HTTPStatusCodeKey, attribute.IntValue(200)},
+	}
+	for _, tt := range tests {
+		if got, ok := spanAttr(span.Attributes, tt.key); !ok || got != tt.want {
+			t.Errorf("Span attribute %v is %v, want %v", tt.key, got.Emit(), tt.want.Emit())
+		}
+	}
+	if _, ok := spanAttr(span.Attributes, ErrorCategoryKey); ok {
+		t.Errorf("Span should not have an error category")
+	}
+
+	metrics := collect(t, reader)
+	hist, ok := metrics["onesignal.client.duration"].(metricdata.Histogram[float64])
+	if !ok || len(hist.DataPoints) != 1 {
+		t.Fatalf("onesignal.client.duration should have 1 data point, got %+v", metrics["onesignal.client.duration"])
+	}
+	if got, want := hist.DataPoints[0].Count, uint64(1); got != want {
+		t.Errorf("Duration count is %v, want %v", got, want)
+	}
+	if _, ok := metrics["onesignal.client.errors"]; ok {
+		t.Errorf("onesignal.client.errors should not be recorded")
+	}
+}
+
+func TestMiddleware_error(t *testing.T) {
+	client, exporter, reader := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"errors": ["No user with this id found"]}`)
+	})
+
+	_, _, err := client.Players.List(&onesignal.PlayerListOptions{AppID: "id123", Limit: 10})
+	if err == nil {
+		t.Fatalf("List should return an error")
+	}
+
+	span := exporter.GetSpans()[0]
+	if got, want := span.Name, "players.list"; got != want {
+		t.Errorf("Span name is %v, want %v", got, want)
+	}
+	if got, want := span.Status.Code, codes.Error; got != want {
+		t.Errorf("Span status is %v, want %v", got, want)
+	}
+	if got, ok := spanAttr(span.Attributes, ErrorCategoryKey); !ok || got.AsString() != "not_found" {
+		t.Errorf("Span error category is %v, want not_found", got.Emit())
+	}
+
+	metrics := collect(t, reader)
+	sum, ok := metrics["onesignal.client.errors"].(metricdata.Sum[int64])
+	if !ok || len(sum.DataPoints) != 1 {
+		t.Fatalf("onesignal.client.errors should have 1 data point, got %+v", metrics["onesignal.client.errors"])
+	}
+	dp := sum.DataPoints[0]
+	if got, want := dp.Value, int64(1); got != want {
+		t.Errorf("Errors count is %v, want %v", got, want)
+	}
+	if got, _ := dp.Attributes.Value(OperationKey); got.AsString() != "players.list" {
+		t.Errorf("Errors operation is %v, want players.list", got.Emit())
+	}
+	if got, _ := dp.Attributes.Value(ErrorCategoryKey); got.AsString() != "not_found" {
+		t.Errorf("Errors category is %v, want not_found", got.Emit())
+	}
+}
+
+func TestMiddleware_propagatesSpanContext(t *testing.T) {
+	client, exporter, _ := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[]`)
+	})
+
+	var inner bool
+	client.Use(func(next onesignal.Doer) onesignal.Doer {
+		return onesignal.DoerFunc(func(r *http.Request) (*http.Response, error) {
+			inner = trace.SpanFromContext(r.Context()).SpanContext().IsValid()
+			return next.Do(r)
+		})
+	})
+
+	client.Apps.List()
+
+	if len(exporter.GetSpans()) != 1 {
+		t.Fatalf("Got %d spans, want 1", len(exporter.GetSpans()))
+	}
+	if !inner {
+		t.Errorf("Inner middlewares should see the span in the request context")
+	}
+}
@@ -0,0 +1,201 @@
+package onesignal
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// OutcomesService handles communication with the outcome related
+// methods of the OneSignal API.
+type OutcomesService struct {
+	client *Client
+}
+
+// Names of the outcomes OneSignal measures for every app. Custom outcomes
+// have the names the app sends them with.
+const (
+	OutcomeClicks              = "os__click"
+	OutcomeSessionDuration     = "os__session_duration"
+	OutcomeConfirmedDeliveries = "os__confirmed_delivery"
+)
+
+// OutcomeAggregation is how the values of an outcome are aggregated.
+type OutcomeAggregation string
+
+const (
+	// OutcomeCount counts the occurrences of an outcome.
+	OutcomeCount OutcomeAggregation = "count"
+
+	// OutcomeSum sums the values of an outcome.
+	OutcomeSum OutcomeAggregation = "sum"
+)
+
+// OutcomeTimeRange is the period outcomes are aggregated over, up to now.
+type OutcomeTimeRange string
+
+const (
+	OutcomeLastHour  OutcomeTimeRange = "1h"
+	OutcomeLastDay   OutcomeTimeRange = "1d"
+	OutcomeLastMonth OutcomeTimeRange = "1mo"
+)
+
+// OutcomeAttribution selects outcomes according to the notifications they
+// are attributed to.
+type OutcomeAttribution string
+
+const (
+	// OutcomeAttributionTotal selects every outcome.
+	OutcomeAttributionTotal OutcomeAttribution = "total"
+
+	// OutcomeAttributionDirect selects the outcomes following a click on a
+	// notification.
+	OutcomeAttributionDirect OutcomeAttribution = "direct"
+
+	// OutcomeAttributionInfluenced selects the outcomes following the
+	// reception of a notification, within the influence window.
+	OutcomeAttributionInfluenced OutcomeAttribution = "influenced"
+
+	// OutcomeAttributionUnattributed selects the outcomes not attributed to
+	// any notification.
+	OutcomeAttributionUnattributed OutcomeAttribution = "unattributed"
+)
+
+// OutcomeQuery names an outcome to fetch and how to aggregate it.
+type OutcomeQuery struct {
+	Name        string
+	Aggregation OutcomeAggregation
+}
+
+// Outcome represents the aggregated value of an outcome.
+type Outcome struct {
+	ID          string             `json:"id"`
+	Value       float64            `json:"value"`
+	Aggregation OutcomeAggregation `json:"aggregation"`
+}
+
+// OutcomeListOptions specifies the parameters to the OutcomesService.List
+// method
+type OutcomeListOptions struct {
+	// Outcomes are the outcomes to fetch. At least one is required.
+	Outcomes []OutcomeQuery
+
+	// TimeRange defaults to OutcomeLastHour.
+	TimeRange OutcomeTimeRange
+
+	// Platforms restricts the outcomes to the devices of the given types,
+	// such as DeviceTypeIOS. All the platforms are included by default.
+	Platforms []int
+
+	// Attribution defaults to OutcomeAttributionTotal.
+	Attribution OutcomeAttribution
+}
+
+// OutcomeListResponse wraps the standard http.Response for the
+// OutcomesService.List method
+type OutcomeListResponse struct {
+	Outcomes []Outcome `json:"outcomes"`
+}
+
+// Validate checks that opt names at least one outcome, each with a known
+// aggregation. It returns a *ValidationError listing every invalid field, or
+// nil.
+//
+// OutcomesService.List calls Validate before sending the request, unless
+// the client was created with WithRequestValidation(false).
+func (opt *OutcomeListOptions) Validate() error {
+	if opt == nil {
+		// validated as empty options, missing the outcomes
+		opt = &OutcomeListOptions{}
+	}
+
+	var errs []*FieldError
+	if len(opt.Outcomes) == 0 {
+		errs = append(errs, &FieldError{Field: "outcome_names", Message: "required"})
+	}
+	for _, o := range opt.Outcomes {
+		switch {
+		case o.Name == "" || strings.Contains(o.Name, ","):
+			errs = append(errs, &FieldError{Field: "outcome_names", Message: fmt.Sprintf("invalid name %q", o.Name)})
+		case o.Aggregation != OutcomeCount && o.Aggregation != OutcomeSum:
+			errs = append(errs, &FieldError{Field: "outcome_names", Message: fmt.Sprintf("invalid aggregation %q for %s", o.Aggregation, o.Name)})
+		}
+	}
+
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+// query returns the query string parameters of opt, none if opt is nil.
+func (opt *OutcomeListOptions) query() url.Values {
+	q := url.Values{}
+	if opt == nil {
+		return q
+	}
+
+	names := make([]string, len(opt.Outcomes))
+	for i, o := range opt.Outcomes {
+		names[i] = o.Name + "." + string(o.Aggregation)
+	}
+	q.Set("outcome_names", strings.Join(names, ","))
+
+	if opt.TimeRange != "" {
+		q.Set("outcome_time_range", string(opt.TimeRange))
+	}
+	if len(opt.Platforms) > 0 {
+		platforms := make([]string, len(opt.Platforms))
+		for i, p := range opt.Platforms {
+			platforms[i] = strconv.Itoa(p)
+		}
+		q.Set("outcome_platforms", strings.Join(platforms, ","))
+	}
+	if opt.Attribution != "" {
+		q.Set("outcome_attribution", string(opt.Attribution))
+	}
+	return q
+}
+
+// List the aggregated outcomes of an app.
+//
+// OneSignal API docs: https://documentation.onesignal.com/reference/view-outcomes
+func (s *OutcomesService) List(appID string, opt *OutcomeListOptions) (*OutcomeListResponse, *http.Response, error) {
+	return s.ListWithContext(context.Background(), appID, opt)
+}
+
+// ListWithContext is like List but takes a context.Context that
+// controls the lifetime of the request.
+func (s *OutcomesService) ListWithContext(ctx context.Context, appID string, opt *OutcomeListOptions) (*OutcomeListResponse, *http.Response, error) {
+	ctx = withOperation(ctx, Operation{Name: "outcomes.list", AppID: appID})
+
+	if !s.client.skipValidation {
+		if err := opt.Validate(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// build the URL with the query string
+	u, err := url.Parse("/apps/" + appID + "/outcomes")
+	if err != nil {
+		return nil, nil, err
+	}
+	u.RawQuery = opt.query().Encode()
+
+	// create the request
+	req, err := s.client.NewRequestWithContext(ctx, "GET", u.String(), nil, APP)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	listRes := &OutcomeListResponse{}
+	resp, err := s.client.Do(req, listRes)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return listRes, resp, err
+}
@@ -0,0 +1,148 @@
+package onesignal
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestOutcomesService_List(t *testing.T) {
+	setup()
+	defer teardown()
+
+	requestSent := false
+
+	appID := "fake-app-id"
+	opt := &OutcomeListOptions{
+		Outcomes: []OutcomeQuery{
+			{Name: OutcomeClicks, Aggregation: OutcomeCount},
+			{Name: "purchase", Aggregation: OutcomeSum},
+		},
+		TimeRange:   OutcomeLastDay,
+		Platforms:   []int{DeviceTypeIOS, DeviceTypeAndroid},
+		Attribution: OutcomeAttributionDirect,
+	}
+
+	mux.HandleFunc("/apps/"+appID+"/outcomes", func(w http.ResponseWriter, r *http.Request) {
+		requestSent = true
+
+		testMethod(t, r, "GET")
+		testHeader(t, r, "Authorization", "Basic "+client.AppKey)
+
+		want := url.Values{
+			"outcome_names":       {"os__click.count,purchase.sum"},
+			"outcome_time_range":  {"1d"},
+			"outcome_platforms":   {"0,1"},
+			"outcome_attribution": {"direct"},
+		}
+		if got := r.URL.Query(); !reflect.DeepEqual(got, want) {
+			t.Errorf("Query: got %v, want %v", got, want)
+		}
+
+		fmt.Fprint(w, `{
+			"outcomes": [
+				{"id": "os__click", "value": 12, "aggregation": "count"},
+				{"id": "purchase", "value": 42.5, "aggregation": "sum"}
+			]
+		}`)
+	})
+
+	listRes, _, err := client.Outcomes.List(appID, opt)
+	if err != nil {
+		t.Errorf("List returned an error: %v", err)
+	}
+
+	want := &OutcomeListResponse{
+		Outcomes: []Outcome{
+			{ID: "os__click", Value: 12, Aggregation: OutcomeCount},
+			{ID: "purchase", Value: 42.5, Aggregation: OutcomeSum},
+		},
+	}
+	if !reflect.DeepEqual(listRes, want) {
+		t.Errorf("List returned %+v, want %+v", listRes, want)
+	}
+
+	if requestSent == false {
+		t.Errorf("Request has not been sent")
+	}
+}
+
+func TestOutcomesService_List_defaults(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/apps/fake-app-id/outcomes", func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.URL.RawQuery, "outcome_names=os__session_duration.sum"; got != want {
+			t.Errorf("Query: got %v, want %v", got, want)
+		}
+		fmt.Fprint(w, `{"outcomes": []}`)
+	})
+
+	opt := &OutcomeListOptions{
+		Outcomes: []OutcomeQuery{{Name: OutcomeSessionDuration, Aggregation: OutcomeSum}},
+	}
+	if _, _, err := client.Outcomes.List("fake-app-id", opt); err != nil {
+		t.Errorf("List returned an error: %v", err)
+	}
+}
+
+func TestOutcomeListOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opt     *OutcomeListOptions
+		wantErr bool
+	}{
+		{"valid", &OutcomeListOptions{Outcomes: []OutcomeQuery{{OutcomeClicks, OutcomeCount}}}, false},
+		{"no outcomes", &OutcomeListOptions{}, true},
+		{"no aggregation", &OutcomeListOptions{Outcomes: []OutcomeQuery{{Name: OutcomeClicks}}}, true},
+		{"unknown aggregation", &OutcomeListOptions{Outcomes: []OutcomeQuery{{OutcomeClicks, "avg"}}}, true},
+		{"comma in name", &OutcomeListOptions{Outcomes: []OutcomeQuery{{"a,b", OutcomeCount}}}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.opt.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate returned %v, want error: %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestOutcomesService_List_invalid(t *testing.T) {
+	setup()
+	defer teardown()
+
+	requestSent := false
+	mux.HandleFunc("/apps/fake-app-id/outcomes", func(w http.ResponseWriter, r *http.Request) {
+		requestSent = true
+	})
+
+	_, _, err := client.Outcomes.List("fake-app-id", &OutcomeListOptions{})
+	if _, ok := err.(*ValidationError); !ok {
+		t.Errorf("List returned %v, want a *ValidationError", err)
+	}
+	if requestSent {
+		t.Errorf("Request should not have been sent")
+	}
+}
+
+func TestOutcomesService_List_nil(t *testing.T) {
+	setup()
+	defer teardown()
+
+	requestSent := false
+	mux.HandleFunc("/apps/fake-app-id/outcomes", func(w http.ResponseWriter, r *http.Request) {
+		requestSent = true
+	})
+
+	_, _, err := client.Outcomes.List("fake-app-id", nil)
+	validationErr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("List returned %v, want a *ValidationError", err)
+	}
+	if len(validationErr.Errors) != 1 || validationErr.Errors[0].Field != "outcome_names" {
+		t.Errorf("List returned %v, want a single outcome_names error", err)
+	}
+	if requestSent {
+		t.Errorf("Request should not have been sent")
+	}
+}
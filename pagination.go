@@ -0,0 +1,143 @@
+package onesignal
+
+import (
+	"context"
+	"iter"
+)
+
+const (
+	defaultPlayersPageSize       = 300
+	defaultNotificationsPageSize = 50
+)
+
+// PageOptions specifies how the iterators returned by PlayersService.All
+// and NotificationsService.All fetch pages.
+type PageOptions struct {
+	// PageSize is the number of items requested per page. It defaults to
+	// the maximum allowed by the endpoint.
+	PageSize int
+
+	// Prefetch makes the iterator fetch the next page in the background
+	// while the current one is being consumed.
+	Prefetch bool
+}
+
+type page[T any] struct {
+	items []T
+	total int
+	err   error
+}
+
+// fetchFunc fetches the items from offset to offset+limit, and returns them
+// along with the total number of items.
+type fetchFunc[T any] func(ctx context.Context, offset, limit int) ([]T, int, error)
+
+// paginate returns an iterator over all the items returned by fetch, page by
+// page. It stops after yielding the first error, which is ctx.Err() if ctx
+// is done.
+func paginate[T any](ctx context.Context, opt *PageOptions, defaultPageSize int, fetch fetchFunc[T]) iter.Seq2[T, error] {
+	pageSize, prefetch := defaultPageSize, false
+	if opt != nil {
+		if opt.PageSize > 0 {
+			pageSize = opt.PageSize
+		}
+		prefetch = opt.Prefetch
+	}
+
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		// stop the prefetching when the caller stops iterating
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		fetchPage := func(offset int) page[T] {
+			items, total, err := fetch(ctx, offset, pageSize)
+			return page[T]{items: items, total: total, err: err}
+		}
+
+		offset := 0
+		var next chan page[T]
+		for {
+			var p page[T]
+			if next != nil {
+				p = <-next
+				next = nil
+			} else {
+				p = fetchPage(offset)
+			}
+			if p.err == nil {
+				p.err = ctx.Err()
+			}
+			if p.err != nil {
+				yield(zero, p.err)
+				return
+			}
+
+			offset += len(p.items)
+			more := len(p.items) > 0 && offset < p.total
+			if more && prefetch {
+				ch := make(chan page[T], 1)
+				go func(offset int) {
+					ch <- fetchPage(offset)
+				}(offset)
+				next = ch
+			}
+
+			for _, item := range p.items {
+				if err := ctx.Err(); err != nil {
+					yield(zero, err)
+					return
+				}
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			if !more {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over all the players of an app. Pages are fetched
+// lazily, as the iteration goes. The iteration stops after the first error,
+// which is the context's error if ctx is done.
+//
+//	for player, err := range client.Players.All(ctx, appID, nil) {
+//		if err != nil {
+//			return err
+//		}
+//		// ...
+//	}
+func (s *PlayersService) All(ctx context.Context, appID string, opt *PageOptions) iter.Seq2[Player, error] {
+	return paginate(ctx, opt, defaultPlayersPageSize, func(ctx context.Context, offset, limit int) ([]Player, int, error) {
+		listRes, _, err := s.ListWithContext(ctx, &PlayerListOptions{
+			AppID:  appID,
+			Limit:  limit,
+			Offset: offset,
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+		return listRes.Players, listRes.TotalCount, nil
+	})
+}
+
+// All returns an iterator over all the notifications of an app. Pages are
+// fetched lazily, as the iteration goes. The iteration stops after the first
+// error, which is the context's error if ctx is done.
+func (s *NotificationsService) All(ctx context.Context, appID string, opt *PageOptions) iter.Seq2[Notification, error] {
+	return paginate(ctx, opt, defaultNotificationsPageSize, func(ctx context.Context, offset, limit int) ([]Notification, int, error) {
+		listRes, _, err := s.ListWithContext(ctx, &NotificationListOptions{
+			AppID:  appID,
+			Limit:  limit,
+			Offset: offset,
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+		return listRes.Notifications, listRes.TotalCount, nil
+	})
+}
@@ -0,0 +1,163 @@
+package onesignal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// handlePlayerPages serves total fake players, page by page.
+func handlePlayerPages(t *testing.T, total int) *[]string {
+	var mu sync.Mutex
+	var requests []string
+	mux.HandleFunc("/players", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		q := r.URL.Query()
+		limit, _ := strconv.Atoi(q.Get("limit"))
+		offset, _ := strconv.Atoi(q.Get("offset"))
+
+		mu.Lock()
+		requests = append(requests, q.Get("app_id")+":"+q.Get("offset")+":"+q.Get("limit"))
+		mu.Unlock()
+
+		fmt.Fprintf(w, `{"total_count": %d, "offset": %d, "limit": %d, "players": [`, total, offset, limit)
+		for i := offset; i < offset+limit && i < total; i++ {
+			if i > offset {
+				fmt.Fprint(w, ",")
+			}
+			fmt.Fprintf(w, `{"id": "player-%d"}`, i)
+		}
+		fmt.Fprint(w, `]}`)
+	})
+	return &requests
+}
+
+func TestPlayersService_All(t *testing.T) {
+	for _, prefetch := range []bool{false, true} {
+		t.Run(fmt.Sprintf("prefetch=%v", prefetch), func(t *testing.T) {
+			setup()
+			defer teardown()
+			requests := handlePlayerPages(t, 5)
+
+			var ids []string
+			for player, err := range client.Players.All(context.Background(), "fake-app-id", &PageOptions{PageSize: 2, Prefetch: prefetch}) {
+				if err != nil {
+					t.Fatalf("All returned an error: %v", err)
+				}
+				ids = append(ids, player.ID)
+			}
+
+			if got, want := fmt.Sprint(ids), "[player-0 player-1 player-2 player-3 player-4]"; got != want {
+				t.Errorf("Players: %v, want %v", got, want)
+			}
+			if got, want := fmt.Sprint(*requests), "[fake-app-id:0:2 fake-app-id:2:2 fake-app-id:4:2]"; got != want {
+				t.Errorf("Requests: %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestPlayersService_All_lazy(t *testing.T) {
+	setup()
+	defer teardown()
+	requests := handlePlayerPages(t, 10)
+
+	for player := range client.Players.All(context.Background(), "fake-app-id", &PageOptions{PageSize: 3}) {
+		if player.ID == "player-1" {
+			break
+		}
+	}
+
+	if got, want := len(*requests), 1; got != want {
+		t.Errorf("Requests: %d, want %d", got, want)
+	}
+}
+
+func TestPlayersService_All_error(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/players", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"errors": ["app_id not found"]}`)
+	})
+
+	count := 0
+	var lastErr error
+	for _, err := range client.Players.All(context.Background(), "fake-app-id", nil) {
+		count++
+		lastErr = err
+	}
+
+	if got, want := count, 1; got != want {
+		t.Errorf("Iterations: %d, want %d", got, want)
+	}
+	if _, ok := lastErr.(*ErrorResponse); !ok {
+		t.Errorf("Error should be of type ErrorResponse but is %v", lastErr)
+	}
+}
+
+func TestPlayersService_All_canceledContext(t *testing.T) {
+	setup()
+	defer teardown()
+	handlePlayerPages(t, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var ids []string
+	var lastErr error
+	for player, err := range client.Players.All(ctx, "fake-app-id", &PageOptions{PageSize: 3, Prefetch: true}) {
+		if err != nil {
+			lastErr = err
+			break
+		}
+		ids = append(ids, player.ID)
+		if len(ids) == 2 {
+			cancel()
+		}
+	}
+
+	if got, want := len(ids), 2; got != want {
+		t.Errorf("Players: %d, want %d", got, want)
+	}
+	if !errors.Is(lastErr, context.Canceled) {
+		t.Errorf("Error is %v, want %v", lastErr, context.Canceled)
+	}
+}
+
+func TestNotificationsService_All(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/notifications", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if got, want := q.Get("limit"), strconv.Itoa(defaultNotificationsPageSize); got != want {
+			t.Errorf("limit: %v, want %v", got, want)
+		}
+		switch q.Get("offset") {
+		case "0":
+			fmt.Fprint(w, `{"total_count": 2, "offset": 0, "limit": 50, "notifications": [{"id": "notif-1"}]}`)
+		case "1":
+			fmt.Fprint(w, `{"total_count": 2, "offset": 1, "limit": 50, "notifications": [{"id": "notif-2"}]}`)
+		default:
+			t.Errorf("Unexpected offset %v", q.Get("offset"))
+		}
+	})
+
+	var ids []string
+	for notification, err := range client.Notifications.All(context.Background(), "fake-app-id", nil) {
+		if err != nil {
+			t.Fatalf("All returned an error: %v", err)
+		}
+		ids = append(ids, notification.ID)
+	}
+
+	if got, want := fmt.Sprint(ids), "[notif-1 notif-2]"; got != want {
+		t.Errorf("Notifications: %v, want %v", got, want)
+	}
+}
@@ -1,6 +1,7 @@
 package onesignal
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -13,6 +14,20 @@ type PlayersService struct {
 	client *Client
 }
 
+// Device types of a Player, in Player.DeviceType and PlayerRequest.DeviceType.
+const (
+	DeviceTypeIOS             = 0
+	DeviceTypeAndroid         = 1
+	DeviceTypeAmazon          = 2
+	DeviceTypeWindowsPhone    = 3
+	DeviceTypeChromeApp       = 4
+	DeviceTypeChromeWeb       = 5
+	DeviceTypeWindowsPhoneWNS = 6
+	DeviceTypeSafari          = 7
+	DeviceTypeFirefox         = 8
+	DeviceTypeMacOS           = 9
+)
+
 // Player represents a OneSignal player.
 type Player struct {
 	ID                string            `json:"id"`
@@ -33,6 +48,10 @@ type Player struct {
 	CreatedAt         int               `json:"created_at"`
 	InvalidIdentifier bool              `json:"invalid_identifier"`
 	BadgeCount        int               `json:"badge_count"`
+
+	// Extra holds the columns of a players export that have no field in
+	// Player, such as the ones requested with ExtraFields.
+	Extra map[string]string `json:"-"`
 }
 
 // PlayerRequest represents a request to create/update a player.
@@ -120,6 +139,17 @@ type PlayerOnFocusOptions struct {
 // PlayersService.CSVExport method
 type PlayerCSVExportOptions struct {
 	AppID string `json:"app_id"`
+
+	// ExtraFields adds columns to the export, such as "country",
+	// "external_user_id" or "notification_types".
+	ExtraFields []string `json:"extra_fields,omitempty"`
+
+	// LastActiveSince restricts the export to the players active since
+	// this Unix timestamp, in seconds.
+	LastActiveSince string `json:"last_active_since,omitempty"`
+
+	// SegmentName restricts the export to the players of a segment.
+	SegmentName string `json:"segment_name,omitempty"`
 }
 
 // PlayerCSVExportResponse wraps the standard http.Response for the
@@ -132,6 +162,14 @@ type PlayerCSVExportResponse struct {
 //
 // OneSignal API docs: https://documentation.onesignal.com/docs/players-view-devices
 func (s *PlayersService) List(opt *PlayerListOptions) (*PlayerListResponse, *http.Response, error) {
+	return s.ListWithContext(context.Background(), opt)
+}
+
+// ListWithContext is like List but takes a context.Context that
+// controls the lifetime of the request.
+func (s *PlayersService) ListWithContext(ctx context.Context, opt *PlayerListOptions) (*PlayerListResponse, *http.Response, error) {
+	ctx = withOperation(ctx, Operation{Name: "players.list", AppID: opt.AppID})
+
 	// build the URL with the query string
 	u, err := url.Parse("/players")
 	if err != nil {
@@ -144,7 +182,7 @@ func (s *PlayersService) List(opt *PlayerListOptions) (*PlayerListResponse, *htt
 	u.RawQuery = q.Encode()
 
 	// create the request
-	req, err := s.client.NewRequest("GET", u.String(), nil, APP)
+	req, err := s.client.NewRequestWithContext(ctx, "GET", u.String(), nil, APP)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -162,6 +200,14 @@ func (s *PlayersService) List(opt *PlayerListOptions) (*PlayerListResponse, *htt
 //
 // OneSignal API docs: https://documentation.onesignal.com/docs/playersid
 func (s *PlayersService) Get(playerID string) (*Player, *http.Response, error) {
+	return s.GetWithContext(context.Background(), playerID)
+}
+
+// GetWithContext is like Get but takes a context.Context that
+// controls the lifetime of the request.
+func (s *PlayersService) GetWithContext(ctx context.Context, playerID string) (*Player, *http.Response, error) {
+	ctx = withOperation(ctx, Operation{Name: "players.get"})
+
 	// build the URL
 	path := fmt.Sprintf("/players/%s", playerID)
 	u, err := url.Parse(path)
@@ -170,7 +216,7 @@ func (s *PlayersService) Get(playerID string) (*Player, *http.Response, error) {
 	}
 
 	// create the request
-	req, err := s.client.NewRequest("GET", u.String(), nil, APP)
+	req, err := s.client.NewRequestWithContext(ctx, "GET", u.String(), nil, APP)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -190,6 +236,18 @@ func (s *PlayersService) Get(playerID string) (*Player, *http.Response, error) {
 // OneSignal API docs:
 // https://documentation.onesignal.com/docs/players-add-a-device
 func (s *PlayersService) Create(player *PlayerRequest) (*PlayerCreateResponse, *http.Response, error) {
+	return s.CreateWithContext(context.Background(), player)
+}
+
+// CreateWithContext is like Create but takes a context.Context that
+// controls the lifetime of the request.
+func (s *PlayersService) CreateWithContext(ctx context.Context, player *PlayerRequest) (*PlayerCreateResponse, *http.Response, error) {
+	op := Operation{Name: "players.create"}
+	if player != nil {
+		op.AppID = player.AppID
+	}
+	ctx = withOperation(ctx, op)
+
 	// build the URL
 	u, err := url.Parse("/players")
 	if err != nil {
@@ -197,7 +255,7 @@ func (s *PlayersService) Create(player *PlayerRequest) (*PlayerCreateResponse, *
 	}
 
 	// create the request
-	req, err := s.client.NewRequest("POST", u.String(), player, APP)
+	req, err := s.client.NewRequestWithContext(ctx, "POST", u.String(), player, APP)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -216,6 +274,14 @@ func (s *PlayersService) Create(player *PlayerRequest) (*PlayerCreateResponse, *
 // OneSignal API docs:
 // https://documentation.onesignal.com/docs/playersidon_session
 func (s *PlayersService) OnSession(playerID string, opt *PlayerOnSessionOptions) (*SuccessResponse, *http.Response, error) {
+	return s.OnSessionWithContext(context.Background(), playerID, opt)
+}
+
+// OnSessionWithContext is like OnSession but takes a context.Context that
+// controls the lifetime of the request.
+func (s *PlayersService) OnSessionWithContext(ctx context.Context, playerID string, opt *PlayerOnSessionOptions) (*SuccessResponse, *http.Response, error) {
+	ctx = withOperation(ctx, Operation{Name: "players.on_session"})
+
 	// build the URL
 	path := fmt.Sprintf("/players/%s/on_session", playerID)
 	u, err := url.Parse(path)
@@ -224,7 +290,7 @@ func (s *PlayersService) OnSession(playerID string, opt *PlayerOnSessionOptions)
 	}
 
 	// create the request
-	req, err := s.client.NewRequest("POST", u.String(), opt, APP)
+	req, err := s.client.NewRequestWithContext(ctx, "POST", u.String(), opt, APP)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -242,6 +308,14 @@ func (s *PlayersService) OnSession(playerID string, opt *PlayerOnSessionOptions)
 //
 // OneSignal API docs: https://documentation.onesignal.com/docs/on_purchase
 func (s *PlayersService) OnPurchase(playerID string, opt *PlayerOnPurchaseOptions) (*SuccessResponse, *http.Response, error) {
+	return s.OnPurchaseWithContext(context.Background(), playerID, opt)
+}
+
+// OnPurchaseWithContext is like OnPurchase but takes a context.Context that
+// controls the lifetime of the request.
+func (s *PlayersService) OnPurchaseWithContext(ctx context.Context, playerID string, opt *PlayerOnPurchaseOptions) (*SuccessResponse, *http.Response, error) {
+	ctx = withOperation(ctx, Operation{Name: "players.on_purchase"})
+
 	// build the URL
 	path := fmt.Sprintf("/players/%s/on_purchase", playerID)
 	u, err := url.Parse(path)
@@ -250,7 +324,7 @@ func (s *PlayersService) OnPurchase(playerID string, opt *PlayerOnPurchaseOption
 	}
 
 	// create the request
-	req, err := s.client.NewRequest("POST", u.String(), opt, APP)
+	req, err := s.client.NewRequestWithContext(ctx, "POST", u.String(), opt, APP)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -269,6 +343,14 @@ func (s *PlayersService) OnPurchase(playerID string, opt *PlayerOnPurchaseOption
 // OneSignal API docs:
 // https://documentation.onesignal.com/docs/playersidon_focus
 func (s *PlayersService) OnFocus(playerID string, opt *PlayerOnFocusOptions) (*SuccessResponse, *http.Response, error) {
+	return s.OnFocusWithContext(context.Background(), playerID, opt)
+}
+
+// OnFocusWithContext is like OnFocus but takes a context.Context that
+// controls the lifetime of the request.
+func (s *PlayersService) OnFocusWithContext(ctx context.Context, playerID string, opt *PlayerOnFocusOptions) (*SuccessResponse, *http.Response, error) {
+	ctx = withOperation(ctx, Operation{Name: "players.on_focus"})
+
 	// build the URL
 	path := fmt.Sprintf("/players/%s/on_focus", playerID)
 	u, err := url.Parse(path)
@@ -277,7 +359,7 @@ func (s *PlayersService) OnFocus(playerID string, opt *PlayerOnFocusOptions) (*S
 	}
 
 	// create the request
-	req, err := s.client.NewRequest("POST", u.String(), opt, APP)
+	req, err := s.client.NewRequestWithContext(ctx, "POST", u.String(), opt, APP)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -296,6 +378,14 @@ func (s *PlayersService) OnFocus(playerID string, opt *PlayerOnFocusOptions) (*S
 // OneSignal API docs:
 // https://documentation.onesignal.com/docs/players_csv_export
 func (s *PlayersService) CSVExport(opt *PlayerCSVExportOptions) (*PlayerCSVExportResponse, *http.Response, error) {
+	return s.CSVExportWithContext(context.Background(), opt)
+}
+
+// CSVExportWithContext is like CSVExport but takes a context.Context that
+// controls the lifetime of the request.
+func (s *PlayersService) CSVExportWithContext(ctx context.Context, opt *PlayerCSVExportOptions) (*PlayerCSVExportResponse, *http.Response, error) {
+	ctx = withOperation(ctx, Operation{Name: "players.csv_export", AppID: opt.AppID})
+
 	// build the URL with the query string
 	u, err := url.Parse("/players/csv_export")
 	if err != nil {
@@ -306,7 +396,7 @@ func (s *PlayersService) CSVExport(opt *PlayerCSVExportOptions) (*PlayerCSVExpor
 	u.RawQuery = q.Encode()
 
 	// create the request
-	req, err := s.client.NewRequest("POST", u.String(), opt, APP)
+	req, err := s.client.NewRequestWithContext(ctx, "POST", u.String(), opt, APP)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -324,6 +414,18 @@ func (s *PlayersService) CSVExport(opt *PlayerCSVExportOptions) (*PlayerCSVExpor
 //
 // OneSignal API docs: https://documentation.onesignal.com/docs/playersid-1
 func (s *PlayersService) Update(playerID string, player *PlayerRequest) (*SuccessResponse, *http.Response, error) {
+	return s.UpdateWithContext(context.Background(), playerID, player)
+}
+
+// UpdateWithContext is like Update but takes a context.Context that
+// controls the lifetime of the request.
+func (s *PlayersService) UpdateWithContext(ctx context.Context, playerID string, player *PlayerRequest) (*SuccessResponse, *http.Response, error) {
+	op := Operation{Name: "players.update"}
+	if player != nil {
+		op.AppID = player.AppID
+	}
+	ctx = withOperation(ctx, op)
+
 	// build the URL
 	path := fmt.Sprintf("/players/%s", playerID)
 	u, err := url.Parse(path)
@@ -332,7 +434,7 @@ func (s *PlayersService) Update(playerID string, player *PlayerRequest) (*Succes
 	}
 
 	// create the request
-	req, err := s.client.NewRequest("PUT", u.String(), player, APP)
+	req, err := s.client.NewRequestWithContext(ctx, "PUT", u.String(), player, APP)
 	if err != nil {
 		return nil, nil, err
 	}
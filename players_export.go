@@ -0,0 +1,216 @@
+package onesignal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// PlayerExportOptions specifies the parameters to the
+// PlayersService.ExportAll method
+type PlayerExportOptions struct {
+	AppID string
+
+	// ExtraFields adds columns to the export, such as "country",
+	// "external_user_id" or "notification_types". They end up in
+	// Player.Extra.
+	ExtraFields []string
+
+	// LastActiveSince restricts the export to the players active since
+	// then, if not zero.
+	LastActiveSince time.Time
+
+	// SegmentName restricts the export to the players of a segment.
+	SegmentName string
+
+	// Poll controls how the export file is polled until OneSignal has
+	// generated it: MaxAttempts bounds the number of downloads attempted,
+	// which are spaced out with an exponential backoff. Retryable and
+	// RetryNonIdempotent are ignored. If nil, the file is polled up to 30
+	// times, starting after 1s and backing off up to 30s.
+	Poll *RetryPolicy
+
+	// HTTPClient downloads the export file. It defaults to the HTTP client
+	// of the Client.
+	HTTPClient *http.Client
+}
+
+// ExportAll returns an iterator over all the players of an app, read from a
+// CSV export. It requests the export with CSVExport, waits for OneSignal to
+// generate the file, then downloads and decodes it as the iteration goes.
+// The iteration stops after the first error, which is the context's error if
+// ctx is done.
+//
+// ExportAll is the way to go for apps with too many players to page through
+// with All.
+func (s *PlayersService) ExportAll(ctx context.Context, opt *PlayerExportOptions) iter.Seq2[Player, error] {
+	return func(yield func(Player, error) bool) {
+		if opt == nil {
+			yield(Player{}, errors.New("onesignal: ExportAll requires PlayerExportOptions"))
+			return
+		}
+
+		exportOpt := &PlayerCSVExportOptions{
+			AppID:       opt.AppID,
+			ExtraFields: opt.ExtraFields,
+			SegmentName: opt.SegmentName,
+		}
+		if !opt.LastActiveSince.IsZero() {
+			exportOpt.LastActiveSince = strconv.FormatInt(opt.LastActiveSince.Unix(), 10)
+		}
+		exportRes, _, err := s.CSVExportWithContext(ctx, exportOpt)
+		if err != nil {
+			yield(Player{}, err)
+			return
+		}
+
+		httpClient, poll := s.client.Client, defaultPollPolicy()
+		if opt.HTTPClient != nil {
+			httpClient = opt.HTTPClient
+		}
+		if opt.Poll != nil {
+			poll = opt.Poll
+		}
+		f, err := pollCSV(ctx, httpClient, exportRes.CSVFileURL, poll)
+		if err != nil {
+			yield(Player{}, err)
+			return
+		}
+		defer f.Close()
+
+		for {
+			row, err := f.next()
+			if err == io.EOF {
+				return
+			}
+			if err == nil {
+				var player Player
+				if player, err = decodeExportedPlayer(f, row); err == nil {
+					if !yield(player, nil) {
+						return
+					}
+					continue
+				}
+			}
+			yield(Player{}, err)
+			return
+		}
+	}
+}
+
+// decodeExportedPlayer decodes a row of a players export.
+func decodeExportedPlayer(f *csvFile, row []string) (Player, error) {
+	var p Player
+	for name, i := range f.columns {
+		if i >= len(row) {
+			continue
+		}
+		v := row[i]
+
+		var err error
+		switch name {
+		case "id":
+			p.ID = v
+		case "identifier":
+			p.Identifier = v
+		case "sdk":
+			p.SDK = v
+		case "language":
+			p.Language = v
+		case "game_version":
+			p.GameVersion = v
+		case "device_os":
+			p.DeviceOS = v
+		case "device_model":
+			p.DeviceModel = v
+		case "ad_id":
+			p.AdID = v
+		case "session_count":
+			p.SessionCount, err = parseCSVInt(v)
+		case "timezone":
+			p.Timezone, err = parseCSVInt(v)
+		case "device_type":
+			p.DeviceType, err = parseCSVInt(v)
+		case "playtime":
+			p.Playtime, err = parseCSVInt(v)
+		case "badge_count":
+			p.BadgeCount, err = parseCSVInt(v)
+		case "last_active":
+			p.LastActive, err = parseCSVTimestamp(v)
+		case "created_at":
+			p.CreatedAt, err = parseCSVTimestamp(v)
+		case "amount_spent":
+			var amount float64
+			if v != "" {
+				amount, err = strconv.ParseFloat(v, 32)
+			}
+			p.AmountSpent = float32(amount)
+		case "invalid_identifier":
+			if v != "" {
+				p.InvalidIdentifier, err = strconv.ParseBool(v)
+			}
+		case "tags":
+			p.Tags, err = parseCSVTags(v)
+		default:
+			if p.Extra == nil {
+				p.Extra = make(map[string]string)
+			}
+			p.Extra[name] = v
+		}
+		if err != nil {
+			return Player{}, fmt.Errorf("onesignal: players export line %d: invalid %s %q: %w", f.line(), name, v, err)
+		}
+	}
+	return p, nil
+}
+
+func parseCSVInt(v string) (int, error) {
+	if v == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(v)
+}
+
+// parseCSVTimestamp parses a Unix timestamp, or a UTC date and time, into a
+// Unix timestamp.
+func parseCSVTimestamp(v string) (int, error) {
+	if v == "" {
+		return 0, nil
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return secs, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04:05", time.RFC3339} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return int(t.Unix()), nil
+		}
+	}
+	return 0, errors.New("invalid timestamp")
+}
+
+// parseCSVTags parses the JSON object of the tags of a player, whose values
+// may be numbers or booleans as well as strings.
+func parseCSVTags(v string) (map[string]string, error) {
+	if v == "" {
+		return nil, nil
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(v), &raw); err != nil {
+		return nil, err
+	}
+	tags := make(map[string]string, len(raw))
+	for key, value := range raw {
+		var s string
+		if err := json.Unmarshal(value, &s); err != nil {
+			s = string(value)
+		}
+		tags[key] = s
+	}
+	return tags, nil
+}
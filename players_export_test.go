@@ -0,0 +1,246 @@
+package onesignal
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestPlayersService_ExportAll(t *testing.T) {
+	setup()
+	defer teardown()
+
+	requestSent := false
+
+	mux.HandleFunc("/players/csv_export", func(w http.ResponseWriter, r *http.Request) {
+		requestSent = true
+
+		testMethod(t, r, "POST")
+		testHeader(t, r, "Authorization", "Basic "+client.AppKey)
+
+		testBody(t, r, &PlayerCSVExportOptions{}, &PlayerCSVExportOptions{
+			AppID:           "id123",
+			ExtraFields:     []string{"country"},
+			LastActiveSince: "1396326002",
+			SegmentName:     "Active Users",
+		})
+
+		fmt.Fprintf(w, `{"csv_file_url": %q}`, server.URL+"/csv_exports/players.csv.gz")
+	})
+
+	polls := 0
+	mux.HandleFunc("/csv_exports/players.csv.gz", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "" {
+			t.Errorf("Authorization header should not be sent, got %q", got)
+		}
+		polls++
+		if polls < 3 {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `<Error><Code>AccessDenied</Code></Error>`)
+			return
+		}
+		gz := gzip.NewWriter(w)
+		fmt.Fprint(gz, "id,identifier,session_count,language,timezone,game_version,device_os,device_type,device_model,ad_id,tags,last_active,playtime,amount_spent,created_at,invalid_identifier,badge_count,sdk,country\n"+
+			`playerid1,token1,4,fr,3600,1.2,11.2,0,iPhone7,adid1,"{""level"":""5"",""premium"":true,""score"":12.5}",2014-04-01 04:20:02,120,1.5,1396326002,f,2,020600,FR`+"\n"+
+			`playerid2,,,,,,,1,,,,,,,,,,,`+"\n")
+		gz.Close()
+	})
+
+	opt := &PlayerExportOptions{
+		AppID:           "id123",
+		ExtraFields:     []string{"country"},
+		LastActiveSince: time.Unix(1396326002, 0),
+		SegmentName:     "Active Users",
+		Poll:            fastPoll,
+	}
+	var players []Player
+	for player, err := range client.Players.ExportAll(context.Background(), opt) {
+		if err != nil {
+			t.Fatalf("ExportAll returned an error: %v", err)
+		}
+		players = append(players, player)
+	}
+
+	want := []Player{
+		{
+			ID:           "playerid1",
+			Identifier:   "token1",
+			SessionCount: 4,
+			Language:     "fr",
+			Timezone:     3600,
+			GameVersion:  "1.2",
+			DeviceOS:     "11.2",
+			DeviceType:   DeviceTypeIOS,
+			DeviceModel:  "iPhone7",
+			AdID:         "adid1",
+			Tags:         map[string]string{"level": "5", "premium": "true", "score": "12.5"},
+			LastActive:   1396326002,
+			Playtime:     120,
+			AmountSpent:  1.5,
+			CreatedAt:    1396326002,
+			BadgeCount:   2,
+			SDK:          "020600",
+			Extra:        map[string]string{"country": "FR"},
+		},
+		{
+			ID:         "playerid2",
+			DeviceType: DeviceTypeAndroid,
+			Extra:      map[string]string{"country": ""},
+		},
+	}
+	if !reflect.DeepEqual(players, want) {
+		t.Errorf("ExportAll returned %+v, want %+v", players, want)
+	}
+
+	if got, want := polls, 3; got != want {
+		t.Errorf("export file polled %d times, want %d", got, want)
+	}
+
+	if requestSent == false {
+		t.Errorf("Request has not been sent")
+	}
+}
+
+func TestPlayersService_ExportAll_httpClient(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/players/csv_export", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"csv_file_url": "https://onesignal-test.s3.amazonaws.com/csv_exports/players.csv"}`)
+	})
+
+	var downloaded string
+	httpClient := &http.Client{
+		Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			downloaded = r.URL.String()
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     make(http.Header),
+				Body:       io.NopCloser(strings.NewReader("id\nplayerid1\n")),
+				Request:    r,
+			}, nil
+		}),
+	}
+
+	opt := &PlayerExportOptions{AppID: "id123", HTTPClient: httpClient, Poll: fastPoll}
+	var ids []string
+	for player, err := range client.Players.ExportAll(context.Background(), opt) {
+		if err != nil {
+			t.Fatalf("ExportAll returned an error: %v", err)
+		}
+		ids = append(ids, player.ID)
+	}
+
+	if got, want := downloaded, "https://onesignal-test.s3.amazonaws.com/csv_exports/players.csv"; got != want {
+		t.Errorf("downloaded %v, want %v", got, want)
+	}
+	if got, want := ids, []string{"playerid1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ExportAll returned %v, want %v", got, want)
+	}
+}
+
+func TestPlayersService_ExportAll_neverReady(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/players/csv_export", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"csv_file_url": %q}`, server.URL+"/csv_exports/players.csv")
+	})
+
+	polls := 0
+	mux.HandleFunc("/csv_exports/players.csv", func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	n := 0
+	for _, err := range client.Players.ExportAll(context.Background(), &PlayerExportOptions{AppID: "id123", Poll: fastPoll}) {
+		n++
+		if !errors.Is(err, ErrNotFound) {
+			t.Errorf("ExportAll returned %v, want %v", err, ErrNotFound)
+		}
+	}
+	if n != 1 {
+		t.Errorf("ExportAll yielded %d times, want 1", n)
+	}
+	if got, want := polls, fastPoll.MaxAttempts; got != want {
+		t.Errorf("export file polled %d times, want %d", got, want)
+	}
+}
+
+func TestPlayersService_ExportAll_invalidValue(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/players/csv_export", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"csv_file_url": %q}`, server.URL+"/csv_exports/players.csv")
+	})
+	mux.HandleFunc("/csv_exports/players.csv", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "id,session_count\nplayerid1,4\nplayerid2,many\n")
+	})
+
+	var err error
+	for _, err = range client.Players.ExportAll(context.Background(), &PlayerExportOptions{AppID: "id123", Poll: fastPoll}) {
+		if err != nil {
+			break
+		}
+	}
+	if got, want := fmt.Sprint(err), `onesignal: players export line 3: invalid session_count "many": strconv.Atoi: parsing "many": invalid syntax`; got != want {
+		t.Errorf("ExportAll returned %v, want %v", got, want)
+	}
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("ExportAll returned %v, want it to wrap %v", err, strconv.ErrSyntax)
+	}
+}
+
+func TestPlayersService_ExportAll_nilOptions(t *testing.T) {
+	setup()
+	defer teardown()
+
+	n := 0
+	for _, err := range client.Players.ExportAll(context.Background(), nil) {
+		n++
+		if err == nil {
+			t.Errorf("ExportAll with nil options should yield an error")
+		}
+	}
+	if n != 1 {
+		t.Errorf("ExportAll yielded %d times, want 1", n)
+	}
+}
+
+func TestPlayersService_ExportAll_canceled(t *testing.T) {
+	setup()
+	defer teardown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	mux.HandleFunc("/players/csv_export", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"csv_file_url": %q}`, server.URL+"/csv_exports/players.csv")
+	})
+	mux.HandleFunc("/csv_exports/players.csv", func(w http.ResponseWriter, r *http.Request) {
+		cancel()
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	poll := &RetryPolicy{MaxAttempts: 5, BaseDelay: time.Hour}
+	for _, err := range client.Players.ExportAll(ctx, &PlayerExportOptions{AppID: "id123", Poll: poll}) {
+		if err != context.Canceled {
+			t.Errorf("ExportAll returned %v, want %v", err, context.Canceled)
+		}
+	}
+}
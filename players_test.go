@@ -1,6 +1,7 @@
 package onesignal
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -217,6 +218,24 @@ func TestPlayersService_Get(t *testing.T) {
 	}
 }
 
+func TestPlayersService_GetWithContext(t *testing.T) {
+	setup()
+	defer teardown()
+
+	playerID := "fake-player-id"
+	ctx, cancel := context.WithCancel(context.Background())
+
+	mux.HandleFunc("/players/"+playerID, func(w http.ResponseWriter, r *http.Request) {
+		cancel()
+		<-r.Context().Done()
+	})
+
+	_, _, err := client.Players.GetWithContext(ctx, playerID)
+	if err != context.Canceled {
+		t.Errorf("GetWithContext returned %v, want %v", err, context.Canceled)
+	}
+}
+
 func TestPlayersService_Create(t *testing.T) {
 	requestSent := false
 
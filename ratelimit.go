@@ -0,0 +1,172 @@
+package onesignal
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimitError is returned when OneSignal rejects a request with a 429 Too
+// Many Requests status.
+type RateLimitError struct {
+	*ErrorResponse
+
+	// RetryAfter is how long to wait before sending another request, as
+	// advertised by the Retry-After header. Zero if the header is absent.
+	RetryAfter time.Duration
+
+	// Limit, Remaining and Reset hold the values of the X-RateLimit-*
+	// headers, if OneSignal sent them.
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	msg := "OneSignal rate limit exceeded"
+	if e.RetryAfter > 0 {
+		msg += fmt.Sprintf(", retry after %v", e.RetryAfter)
+	}
+	if e.ErrorResponse != nil && len(e.Messages) > 0 {
+		msg += ": " + e.ErrorResponse.Error()
+	}
+	return msg
+}
+
+// Unwrap returns the underlying ErrorResponse.
+func (e *RateLimitError) Unwrap() error {
+	return e.ErrorResponse
+}
+
+// newRateLimitError builds a RateLimitError from the headers of r.
+func newRateLimitError(r *http.Response, errResp *ErrorResponse) *RateLimitError {
+	e := &RateLimitError{
+		ErrorResponse: errResp,
+		RetryAfter:    parseRetryAfter(r.Header, time.Now()),
+	}
+	e.Limit, _ = strconv.Atoi(r.Header.Get("X-RateLimit-Limit"))
+	e.Remaining, _ = strconv.Atoi(r.Header.Get("X-RateLimit-Remaining"))
+	if reset, err := strconv.ParseInt(r.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		e.Reset = time.Unix(reset, 0)
+	}
+	return e
+}
+
+// parseRetryAfter parses a Retry-After header, which holds either a number
+// of seconds or an HTTP date.
+func parseRetryAfter(h http.Header, now time.Time) time.Duration {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// RateLimiter is a token bucket that limits the rate at which a Client sends
+// requests, so that bulk jobs stay under the OneSignal quota instead of
+// being throttled. It is safe for concurrent use.
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter returns a RateLimiter allowing rate requests per second on
+// average, with bursts of up to burst requests. rate must be positive and
+// burst at least 1.
+func NewRateLimiter(rate float64, burst int) (*RateLimiter, error) {
+	l := &RateLimiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+	if err := l.validate(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// validate checks that l allows some requests, which the zero RateLimiter
+// doesn't. rate and burst are never changed, so l needs not be locked.
+func (l *RateLimiter) validate() error {
+	if !(l.rate > 0) {
+		return fmt.Errorf("onesignal: invalid rate limiter: rate is %v, it must be positive", l.rate)
+	}
+	if l.burst < 1 {
+		return fmt.Errorf("onesignal: invalid rate limiter: burst is %v, it must be at least 1", l.burst)
+	}
+	return nil
+}
+
+// Wait blocks until a request may be sent or ctx is done. It returns an
+// error right away if l was not created by NewRateLimiter and allows no
+// request, such as the zero RateLimiter.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if err := l.validate(); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+
+	// take the token right away, even if that means going into debt, so
+	// that concurrent callers queue up behind each other
+	l.tokens--
+	if l.tokens >= 0 {
+		l.mu.Unlock()
+		return nil
+	}
+	delay := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.mu.Unlock()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// give the token back
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+type authKeyTypeContextKey struct{}
+
+// rateLimit is the built-in middleware holding every attempt of a request
+// until the RateLimiter configured for its AuthKeyType, if any, allows it.
+func (c *Client) rateLimit(next Doer) Doer {
+	return DoerFunc(func(r *http.Request) (*http.Response, error) {
+		authKeyType, ok := r.Context().Value(authKeyTypeContextKey{}).(AuthKeyType)
+		if l := c.RateLimiters[authKeyType]; ok && l != nil {
+			if err := l.Wait(r.Context()); err != nil {
+				return nil, err
+			}
+		}
+		return next.Do(r)
+	})
+}
@@ -0,0 +1,210 @@
+package onesignal
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckResponse_tooManyRequests(t *testing.T) {
+	r := &http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Header: http.Header{
+			"Retry-After":           []string{"30"},
+			"X-Ratelimit-Limit":     []string{"100"},
+			"X-Ratelimit-Remaining": []string{"0"},
+			"X-Ratelimit-Reset":     []string{"1415914655"},
+		},
+		Body: ioutil.NopCloser(strings.NewReader(`{
+			"errors": ["API rate limit exceeded"]
+		}`)),
+	}
+
+	err, ok := CheckResponse(r).(*RateLimitError)
+	if !ok {
+		t.Fatalf("CheckResponse return value should be of type RateLimitError but is %v: %+v", reflect.TypeOf(err), err)
+	}
+
+	if got, want := err.RetryAfter, 30*time.Second; got != want {
+		t.Errorf("RetryAfter: %v, want %v", got, want)
+	}
+	if got, want := err.Limit, 100; got != want {
+		t.Errorf("Limit: %v, want %v", got, want)
+	}
+	if got, want := err.Remaining, 0; got != want {
+		t.Errorf("Remaining: %v, want %v", got, want)
+	}
+	if got, want := err.Reset, time.Unix(1415914655, 0); !got.Equal(want) {
+		t.Errorf("Reset: %v, want %v", got, want)
+	}
+	if got, want := err.Messages, []string{"API rate limit exceeded"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Messages: %v, want %v", got, want)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2016, time.April, 8, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		header string
+		want   time.Duration
+	}{
+		{"", 0},
+		{"120", 2 * time.Minute},
+		{"-1", 0},
+		{"Fri, 08 Apr 2016 12:00:45 GMT", 45 * time.Second},
+		{"Fri, 08 Apr 2016 11:00:00 GMT", 0},
+		{"soon", 0},
+	}
+	for _, tt := range tests {
+		h := http.Header{}
+		if tt.header != "" {
+			h.Set("Retry-After", tt.header)
+		}
+		if got := parseRetryAfter(h, now); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestDo_retryHonorsRetryAfter(t *testing.T) {
+	setup()
+	defer teardown()
+	client.RetryPolicy = testRetryPolicy()
+
+	var first time.Time
+	attempts := 0
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			first = time.Now()
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		if elapsed := time.Since(first); elapsed < time.Second {
+			t.Errorf("Retried after %v, want at least 1s", elapsed)
+		}
+		fmt.Fprint(w, `{}`)
+	})
+
+	req, _ := client.NewRequest("GET", "/", nil, APP)
+	if _, err := client.Do(req, nil); err != nil {
+		t.Fatalf("Do returned an error: %v", err)
+	}
+
+	if got, want := attempts, 2; got != want {
+		t.Errorf("Attempts: %d, want %d", got, want)
+	}
+}
+
+func TestNewRateLimiter_invalid(t *testing.T) {
+	tests := []struct {
+		rate  float64
+		burst int
+	}{
+		{0, 1},
+		{-1, 1},
+		{math.NaN(), 1},
+		{1, 0},
+		{1, -1},
+	}
+	for _, tt := range tests {
+		if l, err := NewRateLimiter(tt.rate, tt.burst); err == nil || l != nil {
+			t.Errorf("NewRateLimiter(%v, %v) returned %v, %v, want an error", tt.rate, tt.burst, l, err)
+		}
+	}
+}
+
+func TestRateLimiter_Wait(t *testing.T) {
+	l, err := NewRateLimiter(100, 2)
+	if err != nil {
+		t.Fatalf("NewRateLimiter returned an error: %v", err)
+	}
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatalf("Wait returned an error: %v", err)
+		}
+	}
+
+	// 2 requests are allowed right away, the 2 others wait 10ms each
+	if elapsed := time.Since(start); elapsed < 15*time.Millisecond {
+		t.Errorf("4 requests took %v, want at least 15ms", elapsed)
+	}
+}
+
+func TestRateLimiter_Wait_canceledContext(t *testing.T) {
+	l, err := NewRateLimiter(0.001, 1)
+	if err != nil {
+		t.Fatalf("NewRateLimiter returned an error: %v", err)
+	}
+	l.Wait(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Wait returned %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestDo_zeroRateLimiter(t *testing.T) {
+	setup()
+	defer teardown()
+	client.RateLimiters = map[AuthKeyType]*RateLimiter{
+		APP: {},
+	}
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("the request should not be sent")
+	})
+
+	req, _ := client.NewRequest("GET", "/", nil, APP)
+	if _, err := client.Do(req, nil); err == nil || !strings.Contains(err.Error(), "invalid rate limiter") {
+		t.Errorf("Do returned %v, want an invalid rate limiter error", err)
+	}
+}
+
+func TestDo_rateLimitersPerAuthKeyType(t *testing.T) {
+	setup()
+	defer teardown()
+
+	userLimiter, err := NewRateLimiter(0.001, 1)
+	if err != nil {
+		t.Fatalf("NewRateLimiter returned an error: %v", err)
+	}
+	client.RateLimiters = map[AuthKeyType]*RateLimiter{
+		USER: userLimiter,
+	}
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{}`)
+	})
+
+	// APP requests are not limited
+	for i := 0; i < 3; i++ {
+		req, _ := client.NewRequest("GET", "/", nil, APP)
+		if _, err := client.Do(req, nil); err != nil {
+			t.Fatalf("Do returned an error: %v", err)
+		}
+	}
+
+	// USER requests exhaust their bucket after the first one
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req, _ := client.NewRequestWithContext(ctx, "GET", "/", nil, USER)
+	if _, err := client.Do(req, nil); err != nil {
+		t.Fatalf("Do returned an error: %v", err)
+	}
+	req, _ = client.NewRequestWithContext(ctx, "GET", "/", nil, USER)
+	if _, err := client.Do(req, nil); err != context.DeadlineExceeded {
+		t.Errorf("Do returned %v, want %v", err, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,348 @@
+// Package recorder records the HTTP interactions of a onesignal.Client to
+// cassette files, and replays them, so that tests can run realistic flows
+// without network access.
+//
+// A Recorder is an http.RoundTripper. In Record mode, it sends the requests
+// to OneSignal and saves them along with their responses, with the keys and
+// other secrets redacted. In Replay mode, it answers the requests with the
+// saved responses, provided they match a saved request:
+//
+//	rec, err := recorder.New("test-fixtures/cassettes/send-notification.json", recorder.Replay)
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	defer rec.Stop()
+//
+//	client, err := onesignal.NewClient(
+//		onesignal.WithAppKey(appKey),
+//		onesignal.WithHTTPClient(rec.HTTPClient()),
+//	)
+package recorder
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/tbalthazar/onesignal-go"
+)
+
+// Mode tells whether a Recorder records or replays interactions.
+type Mode int
+
+const (
+	// Replay answers the requests with the interactions of the cassette,
+	// without sending them.
+	Replay Mode = iota
+
+	// Record sends the requests and saves the interactions to the
+	// cassette, replacing its content.
+	Record
+)
+
+// Cassette is the content of a cassette file.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a request along with its response.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded HTTP request.
+type Request struct {
+	Method string      `json:"method"`
+	Path   string      `json:"path"`
+	Query  url.Values  `json:"query,omitempty"`
+	Header http.Header `json:"header,omitempty"`
+
+	// Body holds a JSON body, and RawBody any other body.
+	Body    json.RawMessage `json:"body,omitempty"`
+	RawBody []byte          `json:"raw_body,omitempty"`
+}
+
+// Response is a recorded HTTP response.
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+
+	// Body holds a JSON body, and RawBody any other body.
+	Body    json.RawMessage `json:"body,omitempty"`
+	RawBody []byte          `json:"raw_body,omitempty"`
+}
+
+// Recorder records or replays the HTTP interactions of a cassette file. It
+// is safe for concurrent use.
+type Recorder struct {
+	path      string
+	mode      Mode
+	transport http.RoundTripper
+	secrets   []string
+
+	mu       sync.Mutex
+	cassette Cassette
+	replayed []bool
+}
+
+// An Option configures a Recorder created by New.
+type Option func(*Recorder)
+
+// WithTransport sets the transport sending the requests in Record mode,
+// which defaults to http.DefaultTransport.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(r *Recorder) {
+		r.transport = transport
+	}
+}
+
+// WithSecrets adds secrets to redact from the cassette, on top of the keys
+// found in the Authorization headers and the secret fields redacted by
+// onesignal.RedactBody.
+func WithSecrets(secrets ...string) Option {
+	return func(r *Recorder) {
+		r.secrets = append(r.secrets, secrets...)
+	}
+}
+
+// New returns a Recorder for the cassette file at path. In Replay mode, the
+// cassette is read right away.
+func New(path string, mode Mode, opts ...Option) (*Recorder, error) {
+	r := &Recorder{
+		path:      path,
+		mode:      mode,
+		transport: http.DefaultTransport,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if mode == Replay {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("recorder: %v", err)
+		}
+		if err := json.Unmarshal(b, &r.cassette); err != nil {
+			return nil, fmt.Errorf("recorder: invalid cassette %s: %v", path, err)
+		}
+		r.replayed = make([]bool, len(r.cassette.Interactions))
+	}
+	return r, nil
+}
+
+// HTTPClient returns an HTTP client sending its requests through r, to give
+// to onesignal.WithHTTPClient.
+func (r *Recorder) HTTPClient() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Stop writes the cassette in Record mode. In Replay mode, it returns an
+// error if some interactions of the cassette have not been replayed.
+func (r *Recorder) Stop() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.mode == Replay {
+		for i, replayed := range r.replayed {
+			if !replayed {
+				req := r.cassette.Interactions[i].Request
+				return fmt.Errorf("recorder: interaction %d (%s %s) of %s has not been replayed", i, req.Method, req.Path, r.path)
+			}
+		}
+		return nil
+	}
+
+	b, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, append(b, '\n'), 0644)
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if r.mode == Replay {
+		return r.replay(req, body)
+	}
+	return r.record(req, body)
+}
+
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	// send a copy of req, as a RoundTripper must not modify it
+	out := req.Clone(req.Context())
+	if body != nil {
+		out.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	resp, err := r.transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	resp.Request = req
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	secrets := r.secretsOf(req)
+
+	interaction := Interaction{
+		Request: Request{
+			Method: req.Method,
+			Path:   req.URL.Path,
+			Query:  req.URL.Query(),
+			Header: redactHeader(onesignal.RedactHeader(req.Header), secrets),
+		},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     redactHeader(resp.Header, secrets),
+		},
+	}
+	// the length of the body changes once redacted
+	delete(interaction.Response.Header, "Content-Length")
+	interaction.Request.Body, interaction.Request.RawBody = encodeBody(redactBody(body, secrets))
+	interaction.Response.Body, interaction.Response.RawBody = encodeBody(redactBody(respBody, secrets))
+	if len(interaction.Request.Query) == 0 {
+		interaction.Request.Query = nil
+	}
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// the body is compared as it would have been recorded
+	body = redactBody(body, r.secretsOf(req))
+
+	// the interactions are replayed in order, each at most once
+	for i, interaction := range r.cassette.Interactions {
+		if r.replayed[i] || !matches(interaction.Request, req, body) {
+			continue
+		}
+		r.replayed[i] = true
+
+		respBody := []byte(interaction.Response.Body)
+		if respBody == nil {
+			respBody = interaction.Response.RawBody
+		}
+		header := interaction.Response.Header.Clone()
+		if header == nil {
+			header = make(http.Header)
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader(respBody)),
+			ContentLength: int64(len(respBody)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("recorder: no interaction of %s matches %s %s", r.path, req.Method, req.URL.RequestURI())
+}
+
+// matches reports whether req, with the redacted body, matches the recorded request:
+// same method, path and query, and same body once normalized if it is JSON.
+func matches(recorded Request, req *http.Request, body []byte) bool {
+	if recorded.Method != req.Method || recorded.Path != req.URL.Path {
+		return false
+	}
+	query := req.URL.Query()
+	if len(recorded.Query) != 0 || len(query) != 0 {
+		if !reflect.DeepEqual(recorded.Query, query) {
+			return false
+		}
+	}
+
+	if recorded.Body == nil {
+		return bytes.Equal(recorded.RawBody, body)
+	}
+	var want, got interface{}
+	if err := json.Unmarshal(recorded.Body, &want); err != nil {
+		return false
+	}
+	if err := json.Unmarshal(body, &got); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(want, got)
+}
+
+// secretsOf returns the secrets to redact from the interaction of req.
+func (r *Recorder) secretsOf(req *http.Request) []string {
+	return slices.Concat(r.secrets, []string{authorizationKey(req.Header)})
+}
+
+func redactBody(body []byte, secrets []string) []byte {
+	if len(body) == 0 {
+		return nil
+	}
+	return onesignal.RedactBody(body, secrets...)
+}
+
+// encodeBody returns body as JSON if it is, or as raw bytes otherwise.
+func encodeBody(body []byte) (json.RawMessage, []byte) {
+	if len(body) == 0 {
+		return nil, nil
+	}
+	if json.Valid(body) {
+		return json.RawMessage(body), nil
+	}
+	return nil, body
+}
+
+// authorizationKey returns the key of the Authorization header of h.
+func authorizationKey(h http.Header) string {
+	_, key, _ := strings.Cut(h.Get("Authorization"), " ")
+	return key
+}
+
+// redactHeader returns a copy of h with the secrets redacted from its
+// values.
+func redactHeader(h http.Header, secrets []string) http.Header {
+	if len(h) == 0 {
+		return nil
+	}
+	h = h.Clone()
+	for _, values := range h {
+		for i, v := range values {
+			for _, secret := range secrets {
+				if secret != "" {
+					v = strings.ReplaceAll(v, secret, "REDACTED")
+				}
+			}
+			values[i] = v
+		}
+	}
+	return h
+}
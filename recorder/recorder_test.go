@@ -0,0 +1,183 @@
+package recorder
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tbalthazar/onesignal-go"
+)
+
+func newClient(t *testing.T, baseURL string, httpClient *http.Client) *onesignal.Client {
+	client, err := onesignal.NewClient(
+		onesignal.WithAppKey("fake-app-key"),
+		onesignal.WithUserKey("fake-user-key"),
+		onesignal.WithBaseURL(baseURL),
+		onesignal.WithHTTPClient(httpClient),
+	)
+	if err != nil {
+		t.Fatalf("NewClient returned an error: %v", err)
+	}
+	return client
+}
+
+func TestRecorder_recordAndReplay(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/apps/app1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": "app1", "name": "My app", "basic_auth_key": "fake-app-key", "gcm_key": "secret-gcm-key"}`)
+	})
+	mux.HandleFunc("/players/player1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": "player1", "tags": {"level": "5"}}`)
+	})
+	mux.HandleFunc("/export.csv", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "id,language\nplayer1,fr\n")
+	})
+	server := httptest.NewServer(mux)
+
+	path := filepath.Join(t.TempDir(), "cassettes", "flow.json")
+	rec, err := New(path, Record)
+	if err != nil {
+		t.Fatalf("New returned an error: %v", err)
+	}
+	client := newClient(t, server.URL, rec.HTTPClient())
+
+	flow := func() (*onesignal.App, *onesignal.Player, string) {
+		app, _, err := client.Apps.Get("app1")
+		if err != nil {
+			t.Fatalf("Apps.Get returned an error: %v", err)
+		}
+		player, _, err := client.Players.Get("player1")
+		if err != nil {
+			t.Fatalf("Players.Get returned an error: %v", err)
+		}
+		resp, err := client.Client.Get(server.URL + "/export.csv")
+		if err != nil {
+			t.Fatalf("Get returned an error: %v", err)
+		}
+		defer resp.Body.Close()
+		csv, _ := io.ReadAll(resp.Body)
+		return app, player, string(csv)
+	}
+
+	recordedApp, recordedPlayer, recordedCSV := flow()
+	if got, want := recordedApp.BasicAuthKey, "fake-app-key"; got != want {
+		t.Errorf("recorded BasicAuthKey is %v, want %v", got, want)
+	}
+	if err := rec.Stop(); err != nil {
+		t.Fatalf("Stop returned an error: %v", err)
+	}
+	server.Close()
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("cassette not written: %v", err)
+	}
+	for _, secret := range []string{"fake-app-key", "fake-user-key", "secret-gcm-key"} {
+		if strings.Contains(string(b), secret) {
+			t.Errorf("cassette contains %q:\n%s", secret, b)
+		}
+	}
+
+	// replay with the server down
+	rec, err = New(path, Replay)
+	if err != nil {
+		t.Fatalf("New returned an error: %v", err)
+	}
+	client = newClient(t, server.URL, rec.HTTPClient())
+	app, player, csv := flow()
+	if err := rec.Stop(); err != nil {
+		t.Errorf("Stop returned an error: %v", err)
+	}
+
+	recordedApp.BasicAuthKey, recordedApp.GCMKey = "REDACTED", "REDACTED"
+	if !reflect.DeepEqual(app, recordedApp) {
+		t.Errorf("replayed app %+v, want %+v", app, recordedApp)
+	}
+	if !reflect.DeepEqual(player, recordedPlayer) {
+		t.Errorf("replayed player %+v, want %+v", player, recordedPlayer)
+	}
+	if csv != recordedCSV {
+		t.Errorf("replayed CSV %q, want %q", csv, recordedCSV)
+	}
+}
+
+func TestRecorder_replay(t *testing.T) {
+	rec, err := New("test-fixtures/notification-create.json", Replay)
+	if err != nil {
+		t.Fatalf("New returned an error: %v", err)
+	}
+	client := newClient(t, "https://onesignal.com/api/v1", rec.HTTPClient())
+
+	createRes, _, err := client.Notifications.Create(&onesignal.NotificationRequest{
+		AppID:            "id123",
+		Contents:         map[string]string{"en": "Hello"},
+		IncludePlayerIDs: []string{"playerid1"},
+	})
+	if err != nil {
+		t.Fatalf("Notifications.Create returned an error: %v", err)
+	}
+	if got, want := createRes.ID, "notif-id1"; got != want {
+		t.Errorf("ID is %v, want %v", got, want)
+	}
+
+	// the body, the query and the path must match
+	_, _, err = client.Notifications.Get("notif-id1", &onesignal.NotificationGetOptions{AppID: "id456"})
+	if err == nil || !strings.Contains(err.Error(), "recorder: no interaction") {
+		t.Errorf("Notifications.Get returned %v, want a mismatch error", err)
+	}
+	if err := rec.Stop(); err == nil {
+		t.Errorf("Stop should have returned an error")
+	}
+
+	notif, _, err := client.Notifications.Get("notif-id1", &onesignal.NotificationGetOptions{AppID: "id123"})
+	if err != nil {
+		t.Fatalf("Notifications.Get returned an error: %v", err)
+	}
+	if got, want := notif.Successful, 1; got != want {
+		t.Errorf("Successful is %v, want %v", got, want)
+	}
+
+	// each interaction is replayed once
+	_, _, err = client.Notifications.Get("notif-id1", &onesignal.NotificationGetOptions{AppID: "id123"})
+	if err == nil {
+		t.Errorf("Notifications.Get should have returned an error")
+	}
+	if err := rec.Stop(); err != nil {
+		t.Errorf("Stop returned an error: %v", err)
+	}
+}
+
+func TestRecorder_replayBodyMismatch(t *testing.T) {
+	rec, err := New("test-fixtures/notification-create.json", Replay)
+	if err != nil {
+		t.Fatalf("New returned an error: %v", err)
+	}
+	client := newClient(t, "https://onesignal.com/api/v1", rec.HTTPClient())
+
+	_, _, err = client.Notifications.Create(&onesignal.NotificationRequest{
+		AppID:            "id123",
+		Contents:         map[string]string{"en": "Goodbye"},
+		IncludePlayerIDs: []string{"playerid1"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "recorder: no interaction") {
+		t.Errorf("Notifications.Create returned %v, want a mismatch error", err)
+	}
+}
+
+func TestNew_invalidCassette(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "invalid.json")
+	os.WriteFile(path, []byte("{"), 0644)
+
+	if _, err := New(path, Replay); err == nil {
+		t.Errorf("New should have returned an error")
+	}
+	if _, err := New(filepath.Join(t.TempDir(), "missing.json"), Replay); err == nil {
+		t.Errorf("New should have returned an error")
+	}
+}
@@ -0,0 +1,156 @@
+package onesignal
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy specifies how Client.Do retries a request that failed with a
+// network error or a retryable response.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// A value of 1 or less, zero and negative values included, makes a
+	// single attempt: the request is not retried.
+	MaxAttempts int
+
+	// BaseDelay is the delay before the first retry. It doubles on every
+	// subsequent retry, up to MaxDelay.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the backoff delay. Zero means no cap. A longer
+	// Retry-After advertised by a 429 or 503 response is still honored.
+	MaxDelay time.Duration
+
+	// Jitter is the fraction, between 0 and 1, of each delay that is
+	// randomized so that concurrent clients don't retry in lockstep.
+	Jitter float64
+
+	// Retryable reports whether a request should be retried given the
+	// outcome of the last attempt. If nil, DefaultRetryable is used.
+	Retryable func(resp *http.Response, err error) bool
+
+	// RetryNonIdempotent allows retrying POST and PATCH requests, such as
+	// NotificationsService.Create. Retrying them may result in duplicates
+	// (e.g. a notification sent twice), so it is disabled by default.
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy returns a RetryPolicy that makes up to 3 attempts with
+// an exponential backoff starting at 500ms and capped at 10s.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    10 * time.Second,
+		Jitter:      0.5,
+	}
+}
+
+// DefaultRetryable retries network errors, 429 Too Many Requests and 5xx
+// responses. It never retries a canceled or expired context.
+func DefaultRetryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// backoff returns the delay to wait before the given retry (1 for the first
+// retry).
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < retry; i++ {
+		d *= 2
+		if p.MaxDelay > 0 && d >= p.MaxDelay {
+			break
+		}
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+
+	if p.Jitter > 0 {
+		j := p.Jitter
+		if j > 1 {
+			j = 1
+		}
+		d -= time.Duration(j * rand.Float64() * float64(d))
+	}
+	return d
+}
+
+// allows reports whether the policy permits retrying r at all.
+func (p *RetryPolicy) allows(r *http.Request) bool {
+	if p == nil || p.MaxAttempts < 2 {
+		return false
+	}
+	if r.Body != nil && r.GetBody == nil {
+		// the body can't be replayed
+		return false
+	}
+	switch r.Method {
+	case "POST", "PATCH":
+		return p.RetryNonIdempotent
+	}
+	return true
+}
+
+func (p *RetryPolicy) retryable(resp *http.Response, err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(resp, err)
+	}
+	return DefaultRetryable(resp, err)
+}
+
+// retry is the built-in middleware retrying requests according to
+// c.RetryPolicy.
+func (c *Client) retry(next Doer) Doer {
+	return DoerFunc(func(r *http.Request) (*http.Response, error) {
+		p := c.RetryPolicy
+		if !p.allows(r) {
+			return next.Do(r)
+		}
+
+		ctx := r.Context()
+		req := r
+		for attempt := 1; ; attempt++ {
+			resp, err := next.Do(req)
+			if attempt >= p.MaxAttempts || ctx.Err() != nil || !p.retryable(resp, err) {
+				return resp, err
+			}
+
+			// discard the failed response so the connection can be reused,
+			// but honor its Retry-After header
+			delay := p.backoff(attempt)
+			if resp != nil {
+				if retryAfter := parseRetryAfter(resp.Header, time.Now()); retryAfter > delay {
+					delay = retryAfter
+				}
+				io.Copy(io.Discard, resp.Body)
+				resp.Body.Close()
+			}
+
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, ctx.Err()
+			case <-timer.C:
+			}
+
+			// replay the JSON body built by NewRequest
+			req = r.Clone(ctx)
+			if r.GetBody != nil {
+				body, err := r.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				req.Body = body
+			}
+		}
+	})
+}
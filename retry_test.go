@@ -0,0 +1,249 @@
+package onesignal
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func testRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    2 * time.Millisecond,
+	}
+}
+
+func TestDo_retriesServerErrors(t *testing.T) {
+	setup()
+	defer teardown()
+	client.RetryPolicy = testRetryPolicy()
+
+	attempts := 0
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"A":"a"}`)
+	})
+
+	req, _ := client.NewRequest("GET", "/", nil, APP)
+	body := new(struct{ A string })
+	_, err := client.Do(req, body)
+	if err != nil {
+		t.Fatalf("Do returned an error: %v", err)
+	}
+
+	if got, want := attempts, 3; got != want {
+		t.Errorf("Attempts: %d, want %d", got, want)
+	}
+	if got, want := body.A, "a"; got != want {
+		t.Errorf("Response body A = %v, want %v", got, want)
+	}
+}
+
+func TestDo_singleAttempt(t *testing.T) {
+	for _, maxAttempts := range []int{1, 0, -1} {
+		setup()
+		client.RetryPolicy = &RetryPolicy{MaxAttempts: maxAttempts, BaseDelay: time.Millisecond}
+
+		attempts := 0
+		mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+
+		req, _ := client.NewRequest("GET", "/", nil, APP)
+		if _, err := client.Do(req, nil); err == nil {
+			t.Errorf("Do with MaxAttempts %d should have returned an error", maxAttempts)
+		}
+		if got, want := attempts, 1; got != want {
+			t.Errorf("Attempts with MaxAttempts %d: %d, want %d", maxAttempts, got, want)
+		}
+		teardown()
+	}
+}
+
+func TestDo_retryGivesUpAfterMaxAttempts(t *testing.T) {
+	setup()
+	defer teardown()
+	client.RetryPolicy = testRetryPolicy()
+
+	attempts := 0
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	req, _ := client.NewRequest("GET", "/", nil, APP)
+	resp, err := client.Do(req, nil)
+	if err == nil {
+		t.Fatalf("Do should return an error")
+	}
+
+	if got, want := attempts, 3; got != want {
+		t.Errorf("Attempts: %d, want %d", got, want)
+	}
+	if got, want := resp.StatusCode, http.StatusTooManyRequests; got != want {
+		t.Errorf("Status code: %d, want %d", got, want)
+	}
+}
+
+func TestDo_retryDoesNotRetryClientErrors(t *testing.T) {
+	setup()
+	defer teardown()
+	client.RetryPolicy = testRetryPolicy()
+
+	attempts := 0
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadRequest)
+	})
+
+	req, _ := client.NewRequest("GET", "/", nil, APP)
+	client.Do(req, nil)
+
+	if got, want := attempts, 1; got != want {
+		t.Errorf("Attempts: %d, want %d", got, want)
+	}
+}
+
+func TestDo_retryDoesNotRetryPOSTByDefault(t *testing.T) {
+	setup()
+	defer teardown()
+	client.RetryPolicy = testRetryPolicy()
+
+	attempts := 0
+	mux.HandleFunc("/notifications", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	client.Notifications.Create(sampleNotificationRequest)
+
+	if got, want := attempts, 1; got != want {
+		t.Errorf("Attempts: %d, want %d", got, want)
+	}
+}
+
+func TestDo_retryReplaysBodyWhenNonIdempotentAllowed(t *testing.T) {
+	setup()
+	defer teardown()
+	client.RetryPolicy = testRetryPolicy()
+	client.RetryPolicy.RetryNonIdempotent = true
+
+	attempts := 0
+	mux.HandleFunc("/notifications", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		testBody(t, r, &NotificationRequest{}, sampleNotificationRequest)
+		if attempts < 2 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		fmt.Fprint(w, `{"id": "notif-fake-id", "recipients": 1}`)
+	})
+
+	createRes, _, err := client.Notifications.Create(sampleNotificationRequest)
+	if err != nil {
+		t.Fatalf("Create returned an error: %v", err)
+	}
+
+	if got, want := attempts, 2; got != want {
+		t.Errorf("Attempts: %d, want %d", got, want)
+	}
+	if got, want := createRes.ID, "notif-fake-id"; got != want {
+		t.Errorf("ID: %v, want %v", got, want)
+	}
+}
+
+func TestDo_retryCustomPredicate(t *testing.T) {
+	setup()
+	defer teardown()
+	client.RetryPolicy = testRetryPolicy()
+	client.RetryPolicy.Retryable = func(resp *http.Response, err error) bool {
+		return resp != nil && resp.StatusCode == http.StatusConflict
+	}
+
+	attempts := 0
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusConflict)
+	})
+
+	req, _ := client.NewRequest("GET", "/", nil, APP)
+	client.Do(req, nil)
+
+	if got, want := attempts, 3; got != want {
+		t.Errorf("Attempts: %d, want %d", got, want)
+	}
+}
+
+func TestDo_retryStopsOnCanceledContext(t *testing.T) {
+	setup()
+	defer teardown()
+	client.RetryPolicy = &RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Hour,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	attempts := 0
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	// cancel while Do is waiting for the next attempt
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	req, _ := client.NewRequestWithContext(ctx, "GET", "/", nil, APP)
+	_, err := client.Do(req, nil)
+	if err != context.Canceled {
+		t.Errorf("Do returned %v, want %v", err, context.Canceled)
+	}
+
+	if got, want := attempts, 1; got != want {
+		t.Errorf("Attempts: %d, want %d", got, want)
+	}
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	p := &RetryPolicy{
+		BaseDelay: 100 * time.Millisecond,
+		MaxDelay:  time.Second,
+	}
+
+	tests := []struct {
+		retry int
+		want  time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{4, 800 * time.Millisecond},
+		{5, time.Second},
+		{50, time.Second},
+	}
+	for _, tt := range tests {
+		if got := p.backoff(tt.retry); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.retry, got, tt.want)
+		}
+	}
+}
+
+func TestRetryPolicy_backoffJitter(t *testing.T) {
+	p := &RetryPolicy{
+		BaseDelay: 100 * time.Millisecond,
+		Jitter:    0.5,
+	}
+
+	for i := 0; i < 100; i++ {
+		if got := p.backoff(1); got < 50*time.Millisecond || got > 100*time.Millisecond {
+			t.Fatalf("backoff(1) = %v, want between 50ms and 100ms", got)
+		}
+	}
+}
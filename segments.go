@@ -0,0 +1,196 @@
+package onesignal
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SegmentsService handles communication with the segment related
+// methods of the OneSignal API.
+type SegmentsService struct {
+	client *Client
+}
+
+// Segment represents a OneSignal segment, which notifications target by
+// name with NotificationRequest.IncludedSegments and ExcludedSegments.
+type Segment struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	AppID     string    `json:"app_id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	ReadOnly  bool      `json:"read_only"`
+	IsActive  bool      `json:"is_active"`
+}
+
+// SegmentRequest represents a request to create a segment.
+type SegmentRequest struct {
+	Name    string  `json:"name"`
+	Filters Filters `json:"filters"`
+}
+
+// SegmentCreateResponse wraps the standard http.Response for the
+// SegmentsService.Create method
+type SegmentCreateResponse struct {
+	Success bool   `json:"success"`
+	ID      string `json:"id"`
+}
+
+// SegmentListOptions specifies the parameters to the SegmentsService.List
+// method. A nil SegmentListOptions lists the segments with the OneSignal
+// default limit and offset.
+type SegmentListOptions struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
+
+// SegmentListResponse wraps the standard http.Response for the
+// SegmentsService.List method
+type SegmentListResponse struct {
+	TotalCount int `json:"total_count"`
+	Offset     int `json:"offset"`
+	Limit      int `json:"limit"`
+	Segments   []Segment
+}
+
+// Validate checks that r has a name and valid, non-empty filters. It returns
+// a *ValidationError listing every invalid field, or nil.
+//
+// SegmentsService.Create calls Validate before sending the request, unless
+// the client was created with WithRequestValidation(false).
+func (r *SegmentRequest) Validate() error {
+	if r == nil {
+		// validated as an empty request, missing its required fields
+		r = &SegmentRequest{}
+	}
+
+	var errs []*FieldError
+	if r.Name == "" {
+		errs = append(errs, &FieldError{Field: "name", Message: "required"})
+	}
+	if err := r.Filters.Err(); err != nil {
+		errs = append(errs, &FieldError{Field: "filters", Message: strings.TrimPrefix(err.Error(), "onesignal: ")})
+	} else if len(r.Filters.conditions) == 0 {
+		errs = append(errs, &FieldError{Field: "filters", Message: "required"})
+	}
+
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+// List the segments of an app.
+//
+// OneSignal API docs: https://documentation.onesignal.com/reference/view-segments
+func (s *SegmentsService) List(appID string, opt *SegmentListOptions) (*SegmentListResponse, *http.Response, error) {
+	return s.ListWithContext(context.Background(), appID, opt)
+}
+
+// ListWithContext is like List but takes a context.Context that
+// controls the lifetime of the request.
+func (s *SegmentsService) ListWithContext(ctx context.Context, appID string, opt *SegmentListOptions) (*SegmentListResponse, *http.Response, error) {
+	ctx = withOperation(ctx, Operation{Name: "segments.list", AppID: appID})
+
+	// build the URL with the query string
+	u, err := url.Parse("/apps/" + appID + "/segments")
+	if err != nil {
+		return nil, nil, err
+	}
+	if opt != nil {
+		q := u.Query()
+		q.Set("limit", strconv.Itoa(opt.Limit))
+		q.Set("offset", strconv.Itoa(opt.Offset))
+		u.RawQuery = q.Encode()
+	}
+
+	// create the request
+	req, err := s.client.NewRequestWithContext(ctx, "GET", u.String(), nil, APP)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	listRes := &SegmentListResponse{}
+	resp, err := s.client.Do(req, listRes)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return listRes, resp, err
+}
+
+// Create a segment.
+//
+// OneSignal API docs: https://documentation.onesignal.com/reference/create-segments
+func (s *SegmentsService) Create(appID string, opt *SegmentRequest) (*SegmentCreateResponse, *http.Response, error) {
+	return s.CreateWithContext(context.Background(), appID, opt)
+}
+
+// CreateWithContext is like Create but takes a context.Context that
+// controls the lifetime of the request.
+func (s *SegmentsService) CreateWithContext(ctx context.Context, appID string, opt *SegmentRequest) (*SegmentCreateResponse, *http.Response, error) {
+	ctx = withOperation(ctx, Operation{Name: "segments.create", AppID: appID})
+
+	if !s.client.skipValidation {
+		if err := opt.Validate(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// build the URL
+	u, err := url.Parse("/apps/" + appID + "/segments")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// create the request
+	req, err := s.client.NewRequestWithContext(ctx, "POST", u.String(), opt, APP)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	createRes := &SegmentCreateResponse{}
+	resp, err := s.client.Do(req, createRes)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return createRes, resp, err
+}
+
+// Delete a segment.
+//
+// OneSignal API docs: https://documentation.onesignal.com/reference/delete-segments
+func (s *SegmentsService) Delete(appID, segmentID string) (*SuccessResponse, *http.Response, error) {
+	return s.DeleteWithContext(context.Background(), appID, segmentID)
+}
+
+// DeleteWithContext is like Delete but takes a context.Context that
+// controls the lifetime of the request.
+func (s *SegmentsService) DeleteWithContext(ctx context.Context, appID, segmentID string) (*SuccessResponse, *http.Response, error) {
+	ctx = withOperation(ctx, Operation{Name: "segments.delete", AppID: appID})
+
+	// build the URL
+	u, err := url.Parse("/apps/" + appID + "/segments/" + segmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// create the request
+	req, err := s.client.NewRequestWithContext(ctx, "DELETE", u.String(), nil, APP)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	deleteRes := &SuccessResponse{}
+	resp, err := s.client.Do(req, deleteRes)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return deleteRes, resp, err
+}
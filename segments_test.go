@@ -0,0 +1,248 @@
+package onesignal
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/tbalthazar/onesignal-go/testhelper"
+)
+
+var sampleSegment1 = Segment{
+	ID:        "0b2a8f9e-7c1d-4e5f-9a3b-6c8d2e1f4a7b",
+	Name:      "Subscribed Users",
+	AppID:     "92911750-242d-4260-9e00-9d9034f139ce",
+	CreatedAt: t,
+	UpdatedAt: t,
+	ReadOnly:  true,
+	IsActive:  true,
+}
+
+var sampleSegment2 = Segment{
+	ID:        "5d6e7f80-91a2-4b3c-8d4e-5f6a7b8c9d0e",
+	Name:      "French players",
+	AppID:     "92911750-242d-4260-9e00-9d9034f139ce",
+	CreatedAt: t,
+	UpdatedAt: t,
+	IsActive:  true,
+}
+
+func TestSegmentsService_List(t *testing.T) {
+	setup()
+	defer teardown()
+
+	requestSent := false
+
+	appID := "fake-app-id"
+	opt := &SegmentListOptions{
+		Limit:  10,
+		Offset: 0,
+	}
+
+	mux.HandleFunc("/apps/"+appID+"/segments", func(w http.ResponseWriter, r *http.Request) {
+		requestSent = true
+
+		testMethod(t, r, "GET")
+		testHeader(t, r, "Authorization", "Basic "+client.AppKey)
+
+		if got, want := r.URL.RawQuery, "limit=10&offset=0"; got != want {
+			t.Errorf("Query: got %v, want %v", got, want)
+		}
+
+		fmt.Fprint(w, testhelper.LoadFixture(t, "segment-list-response.json"))
+	})
+
+	listRes, _, err := client.Segments.List(appID, opt)
+	if err != nil {
+		t.Errorf("List returned an error: %v", err)
+	}
+
+	want := &SegmentListResponse{
+		TotalCount: 2,
+		Offset:     0,
+		Limit:      10,
+		Segments:   []Segment{sampleSegment1, sampleSegment2},
+	}
+	if !reflect.DeepEqual(listRes, want) {
+		t.Errorf("List returned %+v, want %+v", listRes, want)
+	}
+
+	if requestSent == false {
+		t.Errorf("Request has not been sent")
+	}
+}
+
+func TestSegmentsService_List_nilOptions(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/apps/fake-app-id/segments", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.RawQuery; got != "" {
+			t.Errorf("Query: got %v, want none", got)
+		}
+		fmt.Fprint(w, testhelper.LoadFixture(t, "segment-list-response.json"))
+	})
+
+	listRes, _, err := client.Segments.List("fake-app-id", nil)
+	if err != nil {
+		t.Fatalf("List returned an error: %v", err)
+	}
+	if got, want := len(listRes.Segments), 2; got != want {
+		t.Errorf("List returned %d segments, want %d", got, want)
+	}
+}
+
+func TestSegmentsService_Create(t *testing.T) {
+	setup()
+	defer teardown()
+
+	requestSent := false
+
+	appID := "fake-app-id"
+	segmentRequest := &SegmentRequest{
+		Name:    "French players",
+		Filters: Country("FR").And(SessionCount().GreaterThan("5")),
+	}
+
+	mux.HandleFunc("/apps/"+appID+"/segments", func(w http.ResponseWriter, r *http.Request) {
+		requestSent = true
+
+		testMethod(t, r, "POST")
+		testHeader(t, r, "Authorization", "Basic "+client.AppKey)
+
+		var body struct {
+			Name    string              `json:"name"`
+			Filters []map[string]string `json:"filters"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if got, want := body.Name, segmentRequest.Name; got != want {
+			t.Errorf("Request name: %v, want %v", got, want)
+		}
+		wantFilters := []map[string]string{
+			{"field": "country", "relation": "=", "value": "FR"},
+			{"field": "session_count", "relation": ">", "value": "5"},
+		}
+		if !reflect.DeepEqual(body.Filters, wantFilters) {
+			t.Errorf("Request filters: %+v, want %+v", body.Filters, wantFilters)
+		}
+
+		fmt.Fprint(w, `{
+			"success": true,
+			"id": "segment-fake-id"
+		}`)
+	})
+
+	createRes, _, err := client.Segments.Create(appID, segmentRequest)
+	if err != nil {
+		t.Errorf("Create returned an error: %v", err)
+	}
+
+	want := &SegmentCreateResponse{
+		Success: true,
+		ID:      "segment-fake-id",
+	}
+	if !reflect.DeepEqual(createRes, want) {
+		t.Errorf("Create returned %+v, want %+v", createRes, want)
+	}
+
+	if requestSent == false {
+		t.Errorf("Request has not been sent")
+	}
+}
+
+func TestSegmentsService_Create_invalid(t *testing.T) {
+	setup()
+	defer teardown()
+
+	requestSent := false
+	mux.HandleFunc("/apps/fake-app-id/segments", func(w http.ResponseWriter, r *http.Request) {
+		requestSent = true
+	})
+
+	_, _, err := client.Segments.Create("fake-app-id", &SegmentRequest{
+		Filters: Language().LessThan("fr"),
+	})
+	validationErr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("Create returned %v, want a *ValidationError", err)
+	}
+	var fields []string
+	for _, fieldErr := range validationErr.Errors {
+		fields = append(fields, fieldErr.Field)
+	}
+	if want := []string{"name", "filters"}; !reflect.DeepEqual(fields, want) {
+		t.Errorf("Invalid fields: %v, want %v", fields, want)
+	}
+
+	if requestSent {
+		t.Errorf("Request should not have been sent")
+	}
+}
+
+func TestSegmentsService_Create_nil(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/apps/fake-app-id/segments", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Request should not have been sent")
+	})
+
+	_, _, err := client.Segments.Create("fake-app-id", nil)
+	validationErr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("Create returned %v, want a *ValidationError", err)
+	}
+	var fields []string
+	for _, fieldErr := range validationErr.Errors {
+		fields = append(fields, fieldErr.Field)
+	}
+	if want := []string{"name", "filters"}; !reflect.DeepEqual(fields, want) {
+		t.Errorf("Invalid fields: %v, want %v", fields, want)
+	}
+}
+
+func TestSegmentRequest_Validate_noFilters(t *testing.T) {
+	err := (&SegmentRequest{Name: "Everyone"}).Validate()
+	if err == nil {
+		t.Errorf("Validate should return an error")
+	}
+}
+
+func TestSegmentsService_Delete(t *testing.T) {
+	setup()
+	defer teardown()
+
+	requestSent := false
+
+	appID := "fake-app-id"
+	segmentID := "segment-fake-id"
+
+	mux.HandleFunc("/apps/"+appID+"/segments/"+segmentID, func(w http.ResponseWriter, r *http.Request) {
+		requestSent = true
+
+		testMethod(t, r, "DELETE")
+		testHeader(t, r, "Authorization", "Basic "+client.AppKey)
+
+		fmt.Fprint(w, `{
+			"success": true
+		}`)
+	})
+
+	want := &SuccessResponse{
+		Success: true,
+	}
+	deleteRes, _, err := client.Segments.Delete(appID, segmentID)
+	if err != nil {
+		t.Errorf("Delete returned an error: %v", err)
+	}
+
+	if !reflect.DeepEqual(deleteRes, want) {
+		t.Errorf("Delete returned %+v, want %+v", deleteRes, want)
+	}
+
+	if requestSent == false {
+		t.Errorf("Request has not been sent")
+	}
+}
@@ -0,0 +1,291 @@
+package onesignal
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// TemplatesService handles communication with the template related
+// methods of the OneSignal API.
+type TemplatesService struct {
+	client *Client
+}
+
+// Template represents a OneSignal notification template. A notification
+// uses a template by setting NotificationRequest.TemplateID to its ID.
+type Template struct {
+	ID            string            `json:"id"`
+	Name          string            `json:"name"`
+	Channel       string            `json:"channel"`
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+	Contents      map[string]string `json:"contents"`
+	Headings      map[string]string `json:"headings"`
+	URL           string            `json:"url"`
+	Data          interface{}       `json:"data"`
+	Buttons       []Button          `json:"buttons"`
+	WebButtons    []WebButton       `json:"web_buttons"`
+	IOSSound      string            `json:"ios_sound"`
+	AndroidSound  string            `json:"android_sound"`
+	SmallIcon     string            `json:"small_icon"`
+	LargeIcon     string            `json:"large_icon"`
+	BigPicture    string            `json:"big_picture"`
+	ChromeWebIcon string            `json:"chrome_web_icon"`
+}
+
+// TemplateRequest represents a request to create/update a template.
+type TemplateRequest struct {
+	AppID         string            `json:"app_id"`
+	Name          string            `json:"name,omitempty"`
+	Contents      map[string]string `json:"contents,omitempty"`
+	Headings      map[string]string `json:"headings,omitempty"`
+	URL           string            `json:"url,omitempty"`
+	Data          interface{}       `json:"data,omitempty"`
+	Buttons       []Button          `json:"buttons,omitempty"`
+	WebButtons    []WebButton       `json:"web_buttons,omitempty"`
+	IOSSound      string            `json:"ios_sound,omitempty"`
+	AndroidSound  string            `json:"android_sound,omitempty"`
+	SmallIcon     string            `json:"small_icon,omitempty"`
+	LargeIcon     string            `json:"large_icon,omitempty"`
+	BigPicture    string            `json:"big_picture,omitempty"`
+	ChromeWebIcon string            `json:"chrome_web_icon,omitempty"`
+}
+
+// TemplateListOptions specifies the parameters to the TemplatesService.List
+// method
+type TemplateListOptions struct {
+	AppID  string `json:"app_id"`
+	Limit  int    `json:"limit"`
+	Offset int    `json:"offset"`
+}
+
+// TemplateListResponse wraps the standard http.Response for the
+// TemplatesService.List method
+type TemplateListResponse struct {
+	TotalCount int `json:"total_count"`
+	Offset     int `json:"offset"`
+	Limit      int `json:"limit"`
+	Templates  []Template
+}
+
+// TemplateGetOptions specifies the parameters to the TemplatesService.Get
+// method
+type TemplateGetOptions struct {
+	AppID string `json:"app_id"`
+}
+
+// TemplateDeleteOptions specifies the parameters to the
+// TemplatesService.Delete method
+type TemplateDeleteOptions struct {
+	AppID string `json:"app_id"`
+}
+
+// List the templates of an app.
+//
+// OneSignal API docs: https://documentation.onesignal.com/reference/view-templates
+func (s *TemplatesService) List(opt *TemplateListOptions) (*TemplateListResponse, *http.Response, error) {
+	return s.ListWithContext(context.Background(), opt)
+}
+
+// ListWithContext is like List but takes a context.Context that
+// controls the lifetime of the request.
+func (s *TemplatesService) ListWithContext(ctx context.Context, opt *TemplateListOptions) (*TemplateListResponse, *http.Response, error) {
+	op := Operation{Name: "templates.list"}
+	if opt != nil {
+		op.AppID = opt.AppID
+	}
+	ctx = withOperation(ctx, op)
+
+	// build the URL with the query string
+	u, err := url.Parse("/templates")
+	if err != nil {
+		return nil, nil, err
+	}
+	q := u.Query()
+	if opt != nil {
+		q.Set("app_id", opt.AppID)
+		q.Set("limit", strconv.Itoa(opt.Limit))
+		q.Set("offset", strconv.Itoa(opt.Offset))
+	}
+	u.RawQuery = q.Encode()
+
+	// create the request
+	req, err := s.client.NewRequestWithContext(ctx, "GET", u.String(), nil, APP)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	listRes := &TemplateListResponse{}
+	resp, err := s.client.Do(req, listRes)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return listRes, resp, err
+}
+
+// Get a single template.
+//
+// OneSignal API docs: https://documentation.onesignal.com/reference/view-template
+func (s *TemplatesService) Get(templateID string, opt *TemplateGetOptions) (*Template, *http.Response, error) {
+	return s.GetWithContext(context.Background(), templateID, opt)
+}
+
+// GetWithContext is like Get but takes a context.Context that
+// controls the lifetime of the request.
+func (s *TemplatesService) GetWithContext(ctx context.Context, templateID string, opt *TemplateGetOptions) (*Template, *http.Response, error) {
+	op := Operation{Name: "templates.get"}
+	if opt != nil {
+		op.AppID = opt.AppID
+	}
+	ctx = withOperation(ctx, op)
+
+	// build the URL with the query string
+	u, err := url.Parse("/templates/" + templateID)
+	if err != nil {
+		return nil, nil, err
+	}
+	q := u.Query()
+	if opt != nil {
+		q.Set("app_id", opt.AppID)
+	}
+	u.RawQuery = q.Encode()
+
+	// create the request
+	req, err := s.client.NewRequestWithContext(ctx, "GET", u.String(), nil, APP)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	template := &Template{}
+	resp, err := s.client.Do(req, template)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return template, resp, err
+}
+
+// Create a template. Only the ID of the returned Template is set.
+//
+// OneSignal API docs: https://documentation.onesignal.com/reference/create-template
+func (s *TemplatesService) Create(opt *TemplateRequest) (*Template, *http.Response, error) {
+	return s.CreateWithContext(context.Background(), opt)
+}
+
+// CreateWithContext is like Create but takes a context.Context that
+// controls the lifetime of the request.
+func (s *TemplatesService) CreateWithContext(ctx context.Context, opt *TemplateRequest) (*Template, *http.Response, error) {
+	op := Operation{Name: "templates.create"}
+	if opt != nil {
+		op.AppID = opt.AppID
+	}
+	ctx = withOperation(ctx, op)
+
+	// build the URL
+	u, err := url.Parse("/templates")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// create the request
+	req, err := s.client.NewRequestWithContext(ctx, "POST", u.String(), opt, APP)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	template := &Template{}
+	resp, err := s.client.Do(req, template)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return template, resp, err
+}
+
+// Update a template. Only the ID of the returned Template is set.
+//
+// OneSignal API docs: https://documentation.onesignal.com/reference/update-a-template
+func (s *TemplatesService) Update(templateID string, opt *TemplateRequest) (*Template, *http.Response, error) {
+	return s.UpdateWithContext(context.Background(), templateID, opt)
+}
+
+// UpdateWithContext is like Update but takes a context.Context that
+// controls the lifetime of the request.
+func (s *TemplatesService) UpdateWithContext(ctx context.Context, templateID string, opt *TemplateRequest) (*Template, *http.Response, error) {
+	op := Operation{Name: "templates.update"}
+	if opt != nil {
+		op.AppID = opt.AppID
+	}
+	ctx = withOperation(ctx, op)
+
+	// build the URL with the query string
+	u, err := url.Parse("/templates/" + templateID)
+	if err != nil {
+		return nil, nil, err
+	}
+	q := u.Query()
+	if opt != nil {
+		q.Set("app_id", opt.AppID)
+	}
+	u.RawQuery = q.Encode()
+
+	// create the request
+	req, err := s.client.NewRequestWithContext(ctx, "PATCH", u.String(), opt, APP)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	template := &Template{}
+	resp, err := s.client.Do(req, template)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return template, resp, err
+}
+
+// Delete a template.
+//
+// OneSignal API docs: https://documentation.onesignal.com/reference/delete-template
+func (s *TemplatesService) Delete(templateID string, opt *TemplateDeleteOptions) (*SuccessResponse, *http.Response, error) {
+	return s.DeleteWithContext(context.Background(), templateID, opt)
+}
+
+// DeleteWithContext is like Delete but takes a context.Context that
+// controls the lifetime of the request.
+func (s *TemplatesService) DeleteWithContext(ctx context.Context, templateID string, opt *TemplateDeleteOptions) (*SuccessResponse, *http.Response, error) {
+	op := Operation{Name: "templates.delete"}
+	if opt != nil {
+		op.AppID = opt.AppID
+	}
+	ctx = withOperation(ctx, op)
+
+	// build the URL with the query string
+	u, err := url.Parse("/templates/" + templateID)
+	if err != nil {
+		return nil, nil, err
+	}
+	q := u.Query()
+	if opt != nil {
+		q.Set("app_id", opt.AppID)
+	}
+	u.RawQuery = q.Encode()
+
+	// create the request
+	req, err := s.client.NewRequestWithContext(ctx, "DELETE", u.String(), nil, APP)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	deleteRes := &SuccessResponse{}
+	resp, err := s.client.Do(req, deleteRes)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return deleteRes, resp, err
+}
@@ -0,0 +1,261 @@
+package onesignal
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/tbalthazar/onesignal-go/testhelper"
+)
+
+var sampleTemplate1 = &Template{
+	ID:        "7ac34a8f-7b35-4d6e-9d37-c5a3e6e8e3f1",
+	Name:      "Welcome",
+	Channel:   "push",
+	CreatedAt: t,
+	UpdatedAt: t,
+	Contents: map[string]string{
+		"en": "Welcome aboard!",
+		"fr": "Bienvenue !",
+	},
+	Headings: map[string]string{
+		"en": "Hello",
+	},
+	URL:     "https://example.com/welcome",
+	Buttons: []Button{{ID: "start", Text: "Get started"}},
+}
+
+// sampleTemplateList holds the templates listed without their contents.
+var sampleTemplateList = []Template{
+	{ID: "7ac34a8f-7b35-4d6e-9d37-c5a3e6e8e3f1", Name: "Welcome", Channel: "push", CreatedAt: t, UpdatedAt: t},
+	{ID: "c2b7e0de-5e2a-4b7f-8a6e-0e9f0c7b1d2a", Name: "Cart reminder", Channel: "push", CreatedAt: t, UpdatedAt: t},
+}
+
+var sampleTemplateRequest = &TemplateRequest{
+	AppID:    "id123",
+	Name:     "Welcome",
+	Contents: map[string]string{"en": "Welcome aboard!"},
+}
+
+func TestTemplatesService_List(t *testing.T) {
+	setup()
+	defer teardown()
+
+	requestSent := false
+
+	opt := &TemplateListOptions{
+		AppID:  "fake-app-id",
+		Limit:  10,
+		Offset: 0,
+	}
+
+	mux.HandleFunc("/templates", func(w http.ResponseWriter, r *http.Request) {
+		requestSent = true
+
+		testMethod(t, r, "GET")
+		testHeader(t, r, "Authorization", "Basic "+client.AppKey)
+
+		// test URL/query string
+		u, _ := url.Parse("/templates")
+		q := u.Query()
+		q.Set("app_id", opt.AppID)
+		q.Set("limit", strconv.Itoa(opt.Limit))
+		q.Set("offset", strconv.Itoa(opt.Offset))
+		u.RawQuery = q.Encode()
+		if got, want := r.URL.String(), u.String(); got != want {
+			t.Errorf("URL: got %v, want %v", got, want)
+		}
+
+		fmt.Fprint(w, testhelper.LoadFixture(t, "template-list-response.json"))
+	})
+
+	listRes, _, err := client.Templates.List(opt)
+	if err != nil {
+		t.Errorf("List returned an error: %v", err)
+	}
+
+	want := &TemplateListResponse{
+		TotalCount: 2,
+		Offset:     0,
+		Limit:      10,
+		Templates:  sampleTemplateList,
+	}
+	if !reflect.DeepEqual(listRes, want) {
+		t.Errorf("List returned %+v, want %+v", listRes, want)
+	}
+
+	if requestSent == false {
+		t.Errorf("Request has not been sent")
+	}
+}
+
+func TestTemplatesService_Get(t *testing.T) {
+	setup()
+	defer teardown()
+
+	requestSent := false
+
+	templateID := "7ac34a8f-7b35-4d6e-9d37-c5a3e6e8e3f1"
+	opt := &TemplateGetOptions{
+		AppID: "fake-app-id",
+	}
+
+	mux.HandleFunc("/templates/"+templateID, func(w http.ResponseWriter, r *http.Request) {
+		requestSent = true
+
+		testMethod(t, r, "GET")
+		testHeader(t, r, "Authorization", "Basic "+client.AppKey)
+
+		if got, want := r.URL.Query().Get("app_id"), opt.AppID; got != want {
+			t.Errorf("app_id: got %v, want %v", got, want)
+		}
+
+		fmt.Fprint(w, testhelper.LoadFixture(t, "template-get-response.json"))
+	})
+
+	template, _, err := client.Templates.Get(templateID, opt)
+	if err != nil {
+		t.Errorf("Get returned an error: %v", err)
+	}
+
+	if !reflect.DeepEqual(template, sampleTemplate1) {
+		t.Errorf("Get returned %+v, want %+v", template, sampleTemplate1)
+	}
+
+	if requestSent == false {
+		t.Errorf("Request has not been sent")
+	}
+}
+
+func TestTemplatesService_GetWithContext(t *testing.T) {
+	setup()
+	defer teardown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, _, err := client.Templates.GetWithContext(ctx, "template-id", &TemplateGetOptions{AppID: "id123"})
+	if err != context.Canceled {
+		t.Errorf("GetWithContext returned %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestTemplatesService_Create(t *testing.T) {
+	setup()
+	defer teardown()
+
+	requestSent := false
+
+	mux.HandleFunc("/templates", func(w http.ResponseWriter, r *http.Request) {
+		requestSent = true
+
+		testMethod(t, r, "POST")
+		testHeader(t, r, "Authorization", "Basic "+client.AppKey)
+
+		testBody(t, r, &TemplateRequest{}, sampleTemplateRequest)
+
+		fmt.Fprint(w, `{
+			"success": true,
+			"id": "template-fake-id"
+		}`)
+	})
+
+	template, _, err := client.Templates.Create(sampleTemplateRequest)
+	if err != nil {
+		t.Errorf("Create returned an error: %v", err)
+	}
+
+	if got, want := template.ID, "template-fake-id"; got != want {
+		t.Errorf("ID: %v, want %v", got, want)
+	}
+
+	if requestSent == false {
+		t.Errorf("Request has not been sent")
+	}
+}
+
+func TestTemplatesService_Update(t *testing.T) {
+	setup()
+	defer teardown()
+
+	requestSent := false
+
+	templateID := "template-fake-id"
+
+	mux.HandleFunc("/templates/"+templateID, func(w http.ResponseWriter, r *http.Request) {
+		requestSent = true
+
+		testMethod(t, r, "PATCH")
+		testHeader(t, r, "Authorization", "Basic "+client.AppKey)
+
+		if got, want := r.URL.Query().Get("app_id"), sampleTemplateRequest.AppID; got != want {
+			t.Errorf("app_id: got %v, want %v", got, want)
+		}
+		testBody(t, r, &TemplateRequest{}, sampleTemplateRequest)
+
+		fmt.Fprint(w, `{
+			"success": true,
+			"id": "template-fake-id"
+		}`)
+	})
+
+	template, _, err := client.Templates.Update(templateID, sampleTemplateRequest)
+	if err != nil {
+		t.Errorf("Update returned an error: %v", err)
+	}
+
+	if got, want := template.ID, templateID; got != want {
+		t.Errorf("ID: %v, want %v", got, want)
+	}
+
+	if requestSent == false {
+		t.Errorf("Request has not been sent")
+	}
+}
+
+func TestTemplatesService_Delete(t *testing.T) {
+	setup()
+	defer teardown()
+
+	requestSent := false
+
+	templateID := "template-fake-id"
+	opt := &TemplateDeleteOptions{
+		AppID: "id123",
+	}
+
+	mux.HandleFunc("/templates/"+templateID, func(w http.ResponseWriter, r *http.Request) {
+		requestSent = true
+
+		testMethod(t, r, "DELETE")
+		testHeader(t, r, "Authorization", "Basic "+client.AppKey)
+
+		if got, want := r.URL.Query().Get("app_id"), opt.AppID; got != want {
+			t.Errorf("app_id: got %v, want %v", got, want)
+		}
+
+		fmt.Fprint(w, `{
+			"success": true
+		}`)
+	})
+
+	want := &SuccessResponse{
+		Success: true,
+	}
+	deleteRes, _, err := client.Templates.Delete(templateID, opt)
+	if err != nil {
+		t.Errorf("Delete returned an error: %v", err)
+	}
+
+	if !reflect.DeepEqual(deleteRes, want) {
+		t.Errorf("Delete returned %+v, want %+v", deleteRes, want)
+	}
+
+	if requestSent == false {
+		t.Errorf("Request has not been sent")
+	}
+}